@@ -0,0 +1,179 @@
+package nag
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// WriteTo writes c to w in JSON.
+// Coefficients are encoded with their MarshalText method, which means that the field K must implement [encoding.TextMarshaler].
+// Note that the monomial order is not saved, and that symbols are saved by their formatted names only.
+func (c *Checkpoint[K]) WriteTo(w io.Writer) (int64, error) {
+	cj := checkpointJSON{
+		Basis:        make([][]termJSON, len(c.g)),
+		Unwanted:     make([]bool, len(c.t)),
+		Sugar:        c.sugar,
+		Obstructions: make([]obstructionJSON, c.b.Len()),
+		Seq:          c.seq,
+		Iter:         c.iter,
+		Complete:     c.complete,
+		Stats:        c.stats,
+		Symbols:      make(map[int]string),
+	}
+	for i, gi := range c.g {
+		for coeff, w := range gi.Terms() {
+			for _, sym := range w {
+				cj.Symbols[int(sym)] = gi.SymbolStringer(sym)
+			}
+
+			m, ok := any(coeff).(encoding.TextMarshaler)
+			if !ok {
+				return 0, errors.Errorf("coefficient %T does not implement encoding.TextMarshaler", coeff)
+			}
+			text, err := m.MarshalText()
+			if err != nil {
+				return 0, errors.Wrap(err, "")
+			}
+			cj.Basis[i] = append(cj.Basis[i], termJSON{Coefficient: string(text), Monomial: fromMonomial(w)})
+		}
+	}
+	for i := range c.t {
+		cj.Unwanted[i] = c.t[i] != nil
+	}
+	for i, o := range c.b.s {
+		cj.Obstructions[i] = obstructionJSON{
+			Seq:    o.seq,
+			I:      o.i,
+			J:      o.j,
+			ILeft:  fromMonomial(o.iLeft),
+			IRight: fromMonomial(o.iRight),
+			JLeft:  fromMonomial(o.jLeft),
+			JRight: fromMonomial(o.jRight),
+		}
+	}
+
+	b, err := json.Marshal(cj)
+	if err != nil {
+		return 0, errors.Wrap(err, "")
+	}
+	n, err := w.Write(b)
+	if err != nil {
+		return int64(n), errors.Wrap(err, "")
+	}
+	return int64(n), nil
+}
+
+// ReadCheckpoint reads a checkpoint written by [Checkpoint.WriteTo].
+// The field K must implement [encoding.TextUnmarshaler], and order must be the same monomial order used when the checkpoint was created.
+func ReadCheckpoint[K Field[K]](r io.Reader, field K, order Order) (*Checkpoint[K], error) {
+	var cj checkpointJSON
+	if err := json.NewDecoder(r).Decode(&cj); err != nil {
+		return nil, errors.Wrap(err, "")
+	}
+	if len(cj.Unwanted) != len(cj.Basis) {
+		return nil, errors.Errorf("%d unwanted flags for %d polynomials", len(cj.Unwanted), len(cj.Basis))
+	}
+
+	c := &Checkpoint[K]{
+		g:        make([]*Polynomial[K], len(cj.Basis)),
+		t:        make([]*Polynomial[K], len(cj.Unwanted)),
+		sugar:    cj.Sugar,
+		b:        obstructionQueue[K]{s: make([]obstruction[K], len(cj.Obstructions)), order: order},
+		seq:      cj.Seq,
+		iter:     cj.Iter,
+		complete: cj.Complete,
+		stats:    cj.Stats,
+	}
+	symbolStringer := func(s Symbol) string { return cj.Symbols[int(s)] }
+	for i, terms := range cj.Basis {
+		c.g[i] = NewPolynomial(field, order)
+		c.g[i].SymbolStringer = symbolStringer
+		for _, term := range terms {
+			coeff := field.NewZero()
+			u, ok := any(coeff).(encoding.TextUnmarshaler)
+			if !ok {
+				return nil, errors.Errorf("coefficient %T does not implement encoding.TextUnmarshaler", coeff)
+			}
+			if err := u.UnmarshalText([]byte(term.Coefficient)); err != nil {
+				return nil, errors.Wrap(err, "")
+			}
+			c.g[i].addTerm(1, PolynomialTerm[K]{Coefficient: coeff, Monomial: toMonomial(term.Monomial)})
+		}
+		if c.g[i].Len() == 0 {
+			return nil, errors.Errorf("zero polynomial %d", i)
+		}
+		if cj.Unwanted[i] {
+			c.t[i] = c.g[i]
+		}
+	}
+	if len(c.g) == 0 {
+		return nil, errors.Errorf("empty basis")
+	}
+	if len(c.sugar) != len(c.g) {
+		return nil, errors.Errorf("%d sugar degrees for %d polynomials", len(c.sugar), len(c.g))
+	}
+	for i, o := range cj.Obstructions {
+		if !(0 <= o.I && o.I < len(c.g) && 0 <= o.J && o.J < len(c.g)) {
+			return nil, errors.Errorf("obstruction %d out of range %#v", i, o)
+		}
+		c.b.s[i] = obstruction[K]{
+			i:      o.I,
+			j:      o.J,
+			iLeft:  toMonomial(o.ILeft),
+			iRight: toMonomial(o.IRight),
+			jLeft:  toMonomial(o.JLeft),
+			jRight: toMonomial(o.JRight),
+			seq:    o.Seq,
+		}
+		c.setPriority(&c.b.s[i])
+	}
+	return c, nil
+}
+
+type checkpointJSON struct {
+	Basis        [][]termJSON
+	Unwanted     []bool
+	Sugar        []int
+	Obstructions []obstructionJSON
+	Seq          int
+	Iter         int
+	Complete     bool
+	Stats        Stats
+	// Symbols maps symbols to their formatted names.
+	Symbols map[int]string
+}
+
+type termJSON struct {
+	Coefficient string
+	Monomial    []int
+}
+
+type obstructionJSON struct {
+	Seq    int
+	I      int
+	J      int
+	ILeft  []int
+	IRight []int
+	JLeft  []int
+	JRight []int
+}
+
+// fromMonomial converts w to a slice of integers, so that it is encoded as a JSON array instead of a base64 string.
+func fromMonomial(w Monomial) []int {
+	s := make([]int, len(w))
+	for i := range w {
+		s[i] = int(w[i])
+	}
+	return s
+}
+
+func toMonomial(s []int) Monomial {
+	w := make(Monomial, len(s))
+	for i := range s {
+		w[i] = Symbol(s[i])
+	}
+	return w
+}
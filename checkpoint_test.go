@@ -0,0 +1,135 @@
+package nag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	tests := []struct {
+		variables map[string]Symbol
+		ideal     []string
+		iters     []int
+	}{
+		// Example 5.12, Mora.
+		{
+			variables: map[string]Symbol{"a": 2, "b": 1},
+			ideal:     []string{"aba - b", "bab - b"},
+			iters:     []int{0, 3, 4, 50},
+		},
+		// The braid relation has an infinite Gröbner basis.
+		{
+			variables: map[string]Symbol{"a": 2, "b": 1},
+			ideal:     []string{"aba - bab"},
+			iters:     []int{5, 12, 30},
+		},
+		// Section 6.5 Polynomials and Rules, NCAlgebra.
+		{
+			variables: map[string]Symbol{"x": 1, "y": 2},
+			ideal:     []string{"xyx - y", "y^2 - x + y"},
+			iters:     []int{1, 7, 13},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := make([]*Polynomial[*Rat], 0, len(test.ideal))
+			for _, s := range test.ideal {
+				ideal = append(ideal, parseMust(test.variables, Deglex, s))
+			}
+
+			c := NewCheckpoint(ideal)
+			for _, maxIter := range test.iters {
+				// Save and restore the checkpoint.
+				var buf bytes.Buffer
+				if _, err := c.WriteTo(&buf); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				var err error
+				c, err = ReadCheckpoint(&buf, NewRat(0, 1), Deglex)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+
+				// Check that resuming from the checkpoint is the same as starting from scratch.
+				basis, complete, stats, err := c.Resume(context.Background(), maxIter, nil)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				wantBasis, wantComplete, wantStats, _ := BuchbergerWithOptions(context.Background(), ideal, maxIter, nil)
+				if len(basis) != len(wantBasis) {
+					t.Fatalf("%d %v %v", maxIter, basis, wantBasis)
+				}
+				for j := range basis {
+					if !basis[j].Equal(wantBasis[j]) {
+						t.Errorf("%d %d %v %v", maxIter, j, basis[j], wantBasis[j])
+					}
+				}
+				if complete != wantComplete {
+					t.Errorf("%d got %v want %v", maxIter, complete, wantComplete)
+				}
+				if stats != wantStats {
+					t.Errorf("%d got %+v want %+v", maxIter, stats, wantStats)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckpointCancel(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "aba - bab")}
+	c := NewCheckpoint(ideal)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, _, err := c.Resume(ctx, 20, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("%v", err)
+	}
+	basis, _, _, err := c.Resume(context.Background(), 20, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	want, _ := Buchberger(ideal, 20)
+	if !slices.EqualFunc(basis, want, func(x, y *Polynomial[*Rat]) bool { return x.Equal(y) }) {
+		t.Errorf("got %v want %v", basis, want)
+	}
+}
+
+func TestCheckpointComplete(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "aba - b"), parseMust(variables, Deglex, "bab - b")}
+	c := NewCheckpoint(ideal)
+	want, complete, _, err := c.Resume(context.Background(), 50, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !complete {
+		t.Fatalf("not complete")
+	}
+
+	// Resuming a finished computation, even after saving it, reports the same basis as complete.
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	c, err = ReadCheckpoint(&buf, NewRat(0, 1), Deglex)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	basis, complete, _, err := c.Resume(context.Background(), 0, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !complete {
+		t.Errorf("not complete")
+	}
+	if !slices.EqualFunc(basis, want, func(x, y *Polynomial[*Rat]) bool { return x.Equal(y) }) {
+		t.Errorf("got %v want %v", basis, want)
+	}
+}
@@ -0,0 +1,184 @@
+package nag
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// A Cofactor is a term c*left*g_i*right in the expression of a polynomial as a combination of the polynomials g in an ideal.
+// A list of cofactors thus certifies that a polynomial belongs to the ideal g.
+type Cofactor[K Field[K]] struct {
+	Coefficient K
+	Left        Monomial
+	// I is the index of the polynomial in the ideal.
+	I     int
+	Right Monomial
+}
+
+// BuchbergerCofactors is like [BuchbergerWithOptions], but in addition tracks how each basis polynomial is obtained from g.
+// For each polynomial basis[k], cofactors[k] expresses it in terms of g:
+//
+//	basis[k] = sum of c*left*g[i]*right for c, left, i, right in cofactors[k]
+//
+// Tracking cofactors requires expanding every reduction step in terms of g, and is therefore considerably slower than [BuchbergerWithOptions].
+// The cofactors can be checked with [VerifyCofactors].
+func BuchbergerCofactors[K Field[K]](ctx context.Context, g []*Polynomial[K], maxIter int, opts *Options) (basis []*Polynomial[K], cofactors [][]Cofactor[K], complete bool, stats Stats, err error) {
+	c, err := newCheckpoint(ctx.Done(), g, true)
+	if err != nil {
+		basis, cofactors = c.basisCofactors(false)
+		return basis, cofactors, false, Stats{Basis: len(basis)}, errors.Wrap(ctx.Err(), "")
+	}
+	return c.resume(ctx, maxIter, opts)
+}
+
+// VerifyCofactors reports whether f equals the combination of the polynomials in g given by cofactors.
+func VerifyCofactors[K Field[K]](g []*Polynomial[K], f *Polynomial[K], cofactors []Cofactor[K]) bool {
+	sum := NewPolynomial(f.field.NewZero(), f.order)
+	for _, cf := range cofactors {
+		if cf.I < 0 || cf.I >= len(g) {
+			return false
+		}
+		sum.add(1, cf.Coefficient, cf.Left, g[cf.I], cf.Right)
+	}
+	return sum.Equal(f)
+}
+
+// IsMemberCofactors is like [IsMember], but rewrites the quotient of f in terms of the original generators of the ideal.
+// The basis and its cofactors are typically those returned by [BuchbergerCofactors].
+// If f is a member, the returned cofactors express f in terms of the original generators, and can be checked with [VerifyCofactors].
+// There must be one list of cofactors for each polynomial in basis, otherwise IsMemberCofactors panics.
+func IsMemberCofactors[K Field[K]](f *Polynomial[K], basis []*Polynomial[K], cofactors [][]Cofactor[K]) (member bool, fCofactors []Cofactor[K]) {
+	if len(cofactors) != len(basis) {
+		panic(fmt.Sprintf("%d cofactors for %d basis polynomials", len(cofactors), len(basis)))
+	}
+	member, quotient := IsMember(f, basis)
+	if !member {
+		return false, nil
+	}
+	sums := make([]cofactorSum[K], len(cofactors))
+	for i, cfs := range cofactors {
+		sums[i] = make(cofactorSum[K], len(cfs))
+		for _, cf := range cfs {
+			sums[i].add(1, cf.Coefficient, cf.Left, cofactorSum[K]{cofactorKey{i: cf.I}: f.field.NewOne()}, cf.Right)
+		}
+	}
+	sum := make(cofactorSum[K])
+	for i := range quotient {
+		for _, q := range quotient[i] {
+			sum.add(1, q.Coefficient, q.Left, sums[i], q.Right)
+		}
+	}
+	return true, sum.cofactors()
+}
+
+// A cofactorSum is a combination of the polynomials in an ideal, keyed by the monomials multiplied on both sides of each polynomial.
+type cofactorSum[K Field[K]] map[cofactorKey]K
+
+type cofactorKey struct {
+	left  string
+	i     int
+	right string
+}
+
+// newCofactorSum returns the combination consisting of only the i'th polynomial in an ideal.
+func newCofactorSum[K Field[K]](i int, r0 K) cofactorSum[K] {
+	return cofactorSum[K]{cofactorKey{i: i}: r0.NewOne()}
+}
+
+// add adds sign*c*left*x*right to s.
+func (s cofactorSum[K]) add(sign int, c K, left Monomial, x cofactorSum[K], right Monomial) {
+	for k, xc := range x {
+		k = cofactorKey{left: left.key() + k.left, i: k.i, right: k.right + right.key()}
+		v, ok := s[k]
+		if !ok {
+			v = c.NewZero()
+		}
+		term := c.NewZero().Mul(c, xc)
+		if sign < 0 {
+			v = v.Sub(v, term)
+		} else {
+			v = v.Add(v, term)
+		}
+
+		if v.Equal(c.NewZero()) {
+			delete(s, k)
+		} else {
+			s[k] = v
+		}
+	}
+}
+
+// scale multiplies s by c.
+func (s cofactorSum[K]) scale(c K) {
+	for k, v := range s {
+		s[k] = c.NewZero().Mul(c, v)
+	}
+}
+
+// cofactors returns s as a list of cofactors, sorted by the index of the polynomial, and then by the monomials.
+func (s cofactorSum[K]) cofactors() []Cofactor[K] {
+	cfs := make([]Cofactor[K], 0, len(s))
+	for k, v := range s {
+		cfs = append(cfs, Cofactor[K]{Coefficient: v, Left: monomialFromKey(k.left), I: k.i, Right: monomialFromKey(k.right)})
+	}
+	slices.SortFunc(cfs, func(x, y Cofactor[K]) int {
+		if c := cmp.Compare(x.I, y.I); c != 0 {
+			return c
+		}
+		if c := slices.Compare(x.Left, y.Left); c != 0 {
+			return c
+		}
+		return slices.Compare(x.Right, y.Right)
+	})
+	return cfs
+}
+
+// reduceCofactors returns the cofactors of the remainder of f divided by g, given the cofactors of f and g, and the quotients of the division.
+func reduceCofactors[K Field[K]](f cofactorSum[K], quotient [][]Quotient[K], g []cofactorSum[K]) cofactorSum[K] {
+	for i := range quotient {
+		for _, q := range quotient[i] {
+			f.add(-1, q.Coefficient, q.Left, g[i], q.Right)
+		}
+	}
+	return f
+}
+
+// sPolynomialCofactors returns the cofactors of the S-polynomial of o, which is fraction-free if gcd is not nil.
+func sPolynomialCofactors[K Field[K]](o obstruction[K], g []*Polynomial[K], cofactors []cofactorSum[K], gcd func(x, y K) K) cofactorSum[K] {
+	ci, cj := sPolynomialCoefficients(g[o.i], g[o.j], g[o.i].field.NewZero(), gcd)
+	s := make(cofactorSum[K])
+	s.add(1, ci, o.iLeft, cofactors[o.i], o.iRight)
+	s.add(-1, cj, o.jLeft, cofactors[o.j], o.jRight)
+	return s
+}
+
+// monicSortedCofactors is like monicSorted, but also scales and sorts the cofactors of g accordingly.
+func monicSortedCofactors[K Field[K]](g []*Polynomial[K], cofactors []cofactorSum[K]) ([]*Polynomial[K], [][]Cofactor[K]) {
+	if cofactors == nil {
+		return monicSorted(g), nil
+	}
+	if len(g) == 0 {
+		return g, [][]Cofactor[K]{}
+	}
+	r0 := g[0].field.NewZero()
+	idx := make([]int, len(g))
+	for i := range g {
+		idx[i] = i
+		inv := r0.NewZero().Inv(g[i].LeadingTerm().Coefficient)
+		g[i].mulScalar(inv, g[i])
+		cofactors[i].scale(inv)
+	}
+	slices.SortFunc(idx, func(i, j int) int { return polynomialCmp(g[i], g[j]) })
+
+	sorted := make([]*Polynomial[K], len(g))
+	cfs := make([][]Cofactor[K], len(g))
+	for k, i := range idx {
+		sorted[k] = g[i]
+		cfs[k] = cofactors[i].cofactors()
+	}
+	return sorted, cfs
+}
@@ -0,0 +1,184 @@
+package nag
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestBuchbergerCofactors(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		for _, fractionFree := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%d_%v", workers, fractionFree), func(t *testing.T) {
+				t.Parallel()
+				testBuchbergerCases(t, false, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+					opts := &Options{Workers: workers, FractionFree: fractionFree}
+					basis, cofactors, complete, _, err := BuchbergerCofactors(context.Background(), test.ideal, test.maxiter, opts)
+					if err != nil {
+						t.Fatalf("%+v", err)
+					}
+					if len(cofactors) != len(basis) {
+						t.Fatalf("%d %d", len(cofactors), len(basis))
+					}
+					for i := range basis {
+						if !VerifyCofactors(test.ideal, basis[i], cofactors[i]) {
+							t.Errorf("%d %v %v", i, basis[i], cofactors[i])
+						}
+					}
+					return basis, complete
+				})
+			})
+		}
+	}
+}
+
+func TestBuchbergerCofactorsGenerators(t *testing.T) {
+	variables := map[string]Symbol{"x": 2, "y": 1}
+	// The cofactors refer to the generators as given, even if they are scaled or redundant.
+	tests := []struct {
+		ideal     []string
+		cofactors [][]Cofactor[*Rat]
+	}{
+		{
+			ideal: []string{"3x^2 - 3y", "xy - x", "y^2 - y"},
+			cofactors: [][]Cofactor[*Rat]{
+				{{Coefficient: NewRat(1, 1), I: 2}},
+				{{Coefficient: NewRat(-1, 3), I: 0, Right: Monomial{2}}, {Coefficient: NewRat(1, 3), Left: Monomial{2}, I: 0}, {Coefficient: NewRat(1, 1), I: 1}},
+				{{Coefficient: NewRat(1, 1), I: 1}},
+				{{Coefficient: NewRat(1, 3), I: 0}},
+			},
+		},
+		{
+			ideal: []string{"x^2 - y", "2x^2 - 2y", "xy - x"},
+			cofactors: [][]Cofactor[*Rat]{
+				{{Coefficient: NewRat(1, 2), I: 1}, {Coefficient: NewRat(-1, 2), I: 1, Right: Monomial{1}}, {Coefficient: NewRat(1, 1), Left: Monomial{2}, I: 2}},
+				{{Coefficient: NewRat(-1, 2), I: 1, Right: Monomial{2}}, {Coefficient: NewRat(1, 2), Left: Monomial{2}, I: 1}, {Coefficient: NewRat(1, 1), I: 2}},
+				{{Coefficient: NewRat(1, 1), I: 2}},
+				{{Coefficient: NewRat(1, 2), I: 1}},
+			},
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := make([]*Polynomial[*Rat], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = parseMust(variables, Deglex, s)
+			}
+			basis, cofactors, _, _, err := BuchbergerCofactors(context.Background(), ideal, math.MaxInt, nil)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if len(cofactors) != len(test.cofactors) {
+				t.Fatalf("%d %v", len(cofactors), cofactors)
+			}
+			for j := range cofactors {
+				if !slices.EqualFunc(cofactors[j], test.cofactors[j], cofactorEqual) {
+					t.Errorf("%d %v got %v want %v", j, basis[j], cofactors[j], test.cofactors[j])
+				}
+				if !VerifyCofactors(ideal, basis[j], cofactors[j]) {
+					t.Errorf("%d %v %v", j, basis[j], cofactors[j])
+				}
+			}
+		})
+	}
+}
+
+func cofactorEqual(x, y Cofactor[*Rat]) bool {
+	return x.Coefficient.Equal(y.Coefficient) && slices.Equal(x.Left, y.Left) && x.I == y.I && slices.Equal(x.Right, y.Right)
+}
+
+func TestVerifyCofactors(t *testing.T) {
+	variables := map[string]Symbol{"x": 2, "y": 1}
+	g := []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")}
+	tests := []struct {
+		f         string
+		cofactors []Cofactor[*Rat]
+		ok        bool
+	}{
+		{
+			f:         "x^2 - y",
+			cofactors: []Cofactor[*Rat]{{Coefficient: NewRat(1, 1), I: 0}},
+			ok:        true,
+		},
+		// x(x^2 - y) - (x^2 - y)x = yx - xy
+		{
+			f: "yx - xy",
+			cofactors: []Cofactor[*Rat]{
+				{Coefficient: NewRat(1, 1), Left: Monomial{2}, I: 0},
+				{Coefficient: NewRat(-1, 1), I: 0, Right: Monomial{2}},
+			},
+			ok: true,
+		},
+		{
+			f: "2yx - 2x + x^2 - y",
+			cofactors: []Cofactor[*Rat]{
+				{Coefficient: NewRat(2, 1), I: 1},
+				{Coefficient: NewRat(1, 1), I: 0},
+			},
+			ok: false,
+		},
+		{
+			f:         "x",
+			cofactors: []Cofactor[*Rat]{{Coefficient: NewRat(1, 1), I: 2}},
+			ok:        false,
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			f := parseMust(variables, Deglex, test.f)
+			if ok := VerifyCofactors(g, f, test.cofactors); ok != test.ok {
+				t.Errorf("got %v want %v", ok, test.ok)
+			}
+		})
+	}
+}
+
+func TestIsMemberCofactors(t *testing.T) {
+	variables := map[string]Symbol{"x": 2, "y": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")}
+	basis, cofactors, _, _, err := BuchbergerCofactors(context.Background(), ideal, math.MaxInt, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tests := []struct {
+		f      string
+		member bool
+	}{
+		{f: "x^2 - y", member: true},
+		{f: "y^2 - y", member: true},
+		{f: "x^3 - xy + 2y^2x - 2yx", member: true},
+		{f: "x", member: false},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			f := parseMust(variables, Deglex, test.f)
+			member, fCofactors := IsMemberCofactors(f, basis, cofactors)
+			if member != test.member {
+				t.Fatalf("got %v want %v", member, test.member)
+			}
+			if member && !VerifyCofactors(ideal, f, fCofactors) {
+				t.Errorf("%v", fCofactors)
+			}
+		})
+	}
+}
+
+func TestIsMemberCofactorsLength(t *testing.T) {
+	variables := map[string]Symbol{"x": 2, "y": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")}
+	basis, cofactors, _, _, err := BuchbergerCofactors(context.Background(), ideal, math.MaxInt, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic")
+		}
+	}()
+	IsMemberCofactors(parseMust(variables, Deglex, "y^2 - y"), basis, cofactors[:len(cofactors)-1])
+}
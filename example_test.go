@@ -1,8 +1,12 @@
 package nag_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/fumin/nag"
 )
@@ -84,6 +88,55 @@ func Example_equation_solving() {
 	// Solution: G-XBX+XB-XAX+AX = 0
 }
 
+func Example_equation_solving_block() {
+	// This example solves the equations of the previous example with a block order.
+	// Instead of eliminating variables one at a time, the block order eliminates all of G, A and B at once.
+	// The polynomials without them are the relations among D, L, X and R alone, and the solution for G is the polynomial whose leading monomial is G.
+	equations := []string{
+		"D^2GD^2 - D^2",
+		"GD^2G - G",
+		"GD^2 - 1 + (1-X)L + XR",
+		"D^2G - 1",
+		"DX - XD - 1",
+		"DA - 1",
+		"AD - 1 + L",
+		"DB + 1",
+		"BD - R + 1",
+		"RX - R",
+		"LX",
+	}
+
+	variables := map[string]nag.Symbol{"D": 1, "L": 2, "X": 3, "A": 4, "B": 5, "R": 6, "G": 7}
+	order := nag.BlockOrder(nag.ElimOrder(), []nag.Symbol{variables["G"], variables["A"], variables["B"]})
+	ideal := make([]*nag.Polynomial[*nag.Rat], len(equations))
+	for i, eq := range equations {
+		ideal[i], _ = nag.Parse(variables, order, eq)
+	}
+	basis, _ := nag.Buchberger(ideal, 50)
+	var relations int
+	for _, p := range basis {
+		if w := p.LeadingTerm().Monomial; len(w) == 1 && w[0] == variables["G"] {
+			fmt.Printf("Solution: %v = 0\n", p)
+		}
+		eliminated := true
+		for _, w := range p.Terms() {
+			for _, s := range w {
+				if s == variables["G"] || s == variables["A"] || s == variables["B"] {
+					eliminated = false
+				}
+			}
+		}
+		if eliminated {
+			relations++
+		}
+	}
+	fmt.Printf("Relations without G, A and B: %d\n", relations)
+
+	// Output:
+	// Solution: G-XBX+XB-XAX+AX = 0
+	// Relations without G, A and B: 16
+}
+
 func Example_minimal_polynomial() {
 	// This example shows how to find the minimal polynomial for α = √2+√3+√5.
 	// The minimal polynomial, P(x), for an algebraic number α is one whose
@@ -165,6 +218,70 @@ func ExampleDeglex() {
 	// -1
 }
 
+func ExampleDegrevlex() {
+	fmt.Println(nag.Degrevlex(nag.Monomial{2, 2, 2}, nag.Monomial{2, 2, 1, 1}))
+	fmt.Println(nag.Degrevlex(nag.Monomial{1, 2, 2}, nag.Monomial{2, 1, 2}))
+	fmt.Println(nag.Degrevlex(nag.Monomial{2, 2, 1}, nag.Monomial{1, 1, 2}))
+
+	// Output:
+	// -1
+	// -1
+	// 1
+}
+
+func ExampleRightDeglex() {
+	fmt.Println(nag.RightDeglex(nag.Monomial{2, 2, 2}, nag.Monomial{2, 2, 1, 1}))
+	fmt.Println(nag.RightDeglex(nag.Monomial{1, 2, 2}, nag.Monomial{2, 1, 2}))
+	fmt.Println(nag.RightDeglex(nag.Monomial{2, 2, 1}, nag.Monomial{1, 1, 2}))
+
+	// Output:
+	// -1
+	// 1
+	// -1
+}
+
+func ExampleWeightedDeglex() {
+	// The symbol 2 weighs as much as three symbols of weight 1.
+	order := nag.WeightedDeglex(map[nag.Symbol]int{2: 3})
+	fmt.Println(order(nag.Monomial{2}, nag.Monomial{1, 1}))
+	fmt.Println(order(nag.Monomial{2}, nag.Monomial{1, 1, 1}))
+	fmt.Println(order(nag.Monomial{1, 2}, nag.Monomial{1, 1, 1, 1}))
+
+	// Output:
+	// 1
+	// 1
+	// 1
+}
+
+func ExampleBlockOrder() {
+	// Let x and y be the sum and product of the commuting variables a and b.
+	// Eliminating a and b at once yields the relations between x and y alone.
+	equations := []string{"x - a - b", "y - ab", "ab - ba"}
+	variables := map[string]nag.Symbol{"x": 1, "y": 2, "a": 3, "b": 4}
+	order := nag.BlockOrder(nag.Deglex, []nag.Symbol{variables["a"], variables["b"]})
+	ideal := make([]*nag.Polynomial[*nag.Rat], len(equations))
+	for i, eq := range equations {
+		ideal[i], _ = nag.Parse(variables, order, eq)
+	}
+	basis, _ := nag.Buchberger(ideal, 50)
+	for _, p := range basis {
+		eliminated := true
+		for _, w := range p.Terms() {
+			for _, s := range w {
+				if s == variables["a"] || s == variables["b"] {
+					eliminated = false
+				}
+			}
+		}
+		if eliminated {
+			fmt.Printf("%v = 0\n", p)
+		}
+	}
+
+	// Output:
+	// yx-xy = 0
+}
+
 func ExamplePolynomial_Terms() {
 	p := nag.NewPolynomial(
 		nag.NewRat(0, 1), nag.Deglex,
@@ -269,6 +386,128 @@ func ExampleBuchberger() {
 	// Basis is complete: true
 }
 
+func ExampleBuchbergerWithOptions() {
+	ideal := []string{
+		"aba - b",
+		"bab - b",
+	}
+	variables := map[string]nag.Symbol{"a": 1, "b": 2}
+	idealP := make([]*nag.Polynomial[*nag.Rat], len(ideal))
+	for i := range ideal {
+		idealP[i], _ = nag.Parse(variables, nag.Deglex, ideal[i])
+	}
+
+	// Stop the computation after one second.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// Monitor the progress of the computation.
+	opts := &nag.Options{Observer: func(s nag.Stats) {
+		if s.Reductions%5 == 0 {
+			fmt.Printf("obstructions: %d, basis: %d\n", s.Obstructions, s.Basis)
+		}
+	}}
+	basis, complete, stats, err := nag.BuchbergerWithOptions(ctx, idealP, 50, opts)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	fmt.Println("Gröbner basis:", basis)
+	fmt.Println("Basis is complete:", complete)
+	fmt.Printf("S-polynomials reduced to zero: %d/%d\n", stats.ZeroReductions, stats.Reductions)
+
+	// Output:
+	// obstructions: 4, basis: 3
+	// Gröbner basis: [ba-ab b^2-ab a^2b-b]
+	// Basis is complete: true
+	// S-polynomials reduced to zero: 6/9
+}
+
+func ExampleCheckpoint() {
+	// The braid relation aba = bab has an infinite Gröbner basis.
+	variables := map[string]nag.Symbol{"a": 2, "b": 1}
+	braid, _ := nag.Parse(variables, nag.Deglex, "aba - bab")
+
+	// Run the first few iterations, and save the state of the computation.
+	checkpoint := nag.NewCheckpoint([]*nag.Polynomial[*nag.Rat]{braid})
+	basis, _, _, _ := checkpoint.Resume(context.Background(), 3, nil)
+	fmt.Println("Gröbner basis after 3 iterations:", basis)
+	var saved bytes.Buffer
+	checkpoint.WriteTo(&saved)
+
+	// Restore the computation, possibly in another process, and continue for a few more iterations.
+	restored, _ := nag.ReadCheckpoint(&saved, nag.NewRat(0, 1), nag.Deglex)
+	basis, _, _, _ = restored.Resume(context.Background(), 6, nil)
+	fmt.Println("Gröbner basis after 6 iterations:", basis)
+
+	// Output:
+	// Gröbner basis after 3 iterations: [aba-bab ab^2ab-bab^2a ab^3ab-bab^2a^2]
+	// Gröbner basis after 6 iterations: [aba-bab ab^2ab-bab^2a ab^3ab-bab^2a^2 ab^4ab-bab^2a^3]
+}
+
+func ExampleBuchbergerCofactors() {
+	ideal := []string{
+		"aba - b",
+		"bab - b",
+	}
+	variables := map[string]nag.Symbol{"a": 1, "b": 2}
+	idealP := make([]*nag.Polynomial[*nag.Rat], len(ideal))
+	for i := range ideal {
+		idealP[i], _ = nag.Parse(variables, nag.Deglex, ideal[i])
+	}
+
+	basis, cofactors, _, _, _ := nag.BuchbergerCofactors(context.Background(), idealP, 10, nil)
+	// Format the monomials of the cofactors with the names of their variables.
+	names := make(map[nag.Symbol]string, len(variables))
+	for name, s := range variables {
+		names[s] = name
+	}
+	symbols := func(w nag.Monomial) string {
+		var b strings.Builder
+		for _, s := range w {
+			b.WriteString(names[s])
+		}
+		return b.String()
+	}
+	// Each basis polynomial is a combination of the input polynomials.
+	for i := range basis {
+		fmt.Printf("%v = ", basis[i])
+		for j, cf := range cofactors[i] {
+			if j > 0 {
+				fmt.Printf(" + ")
+			}
+			fmt.Printf("(%v)%s(g%d)%s", cf.Coefficient, symbols(cf.Left), cf.I, symbols(cf.Right))
+		}
+		fmt.Printf(", verified: %v\n", nag.VerifyCofactors(idealP, basis[i], cofactors[i]))
+	}
+
+	// Output:
+	// ba-ab = (-1)(g0)b + (1)b(g0) + (-1)(g1)a + (1)a(g1), verified: true
+	// b^2-ab = (-1)(g0)b + (1)a(g1), verified: true
+	// a^2b-b = (1)(g0) + (1)a(g0)b + (-1)ab(g0) + (1)a(g1)a + (-1)aa(g1), verified: true
+}
+
+func ExampleSignatureGB() {
+	ideal := []string{
+		"aba - b",
+		"bab - b",
+	}
+	variables := map[string]nag.Symbol{"a": 1, "b": 2}
+	idealP := make([]*nag.Polynomial[*nag.Rat], len(ideal))
+	for i := range ideal {
+		idealP[i], _ = nag.Parse(variables, nag.Deglex, ideal[i])
+	}
+
+	// The signature-based algorithm returns the same basis as the Buchberger algorithm.
+	basis, complete := nag.SignatureGB(idealP, 10)
+	fmt.Println("Gröbner basis:", basis)
+	fmt.Println("Basis is complete:", complete)
+
+	// Output:
+	// Gröbner basis: [ba-ab b^2-ab a^2b-b]
+	// Basis is complete: true
+}
+
 func ExampleBuchbergerHomogeneous() {
 	ideal := []string{
 		"x^2 - 2y^2",
@@ -316,6 +555,31 @@ func ExampleBuchbergerHomogeneous() {
 	// Basis is complete: true
 }
 
+func ExampleBuchbergerHomogenized() {
+	ideal := []string{
+		"x^2 - y",
+		"xy - x",
+	}
+	// The homogenizing symbol h must be smaller than other symbols.
+	variables := map[string]nag.Symbol{"h": 1, "x": 3, "y": 2}
+	idealP := make([]*nag.Polynomial[*nag.Rat], len(ideal))
+	for i := range ideal {
+		idealP[i], _ = nag.Parse(variables, nag.Deglex, ideal[i])
+	}
+	fmt.Println("Homogenized:", nag.Homogenize(idealP[0], variables["h"]))
+
+	// Compute the Gröbner basis of a non-homogeneous ideal by degree.
+	for _, maxDeg := range []int{2, 4} {
+		basis, complete := nag.BuchbergerHomogenized(idealP, variables["h"], maxDeg)
+		fmt.Printf("Gröbner basis truncated at degree %d: %v, complete: %v\n", maxDeg, basis, complete)
+	}
+
+	// Output:
+	// Homogenized: x^2-hy
+	// Gröbner basis truncated at degree 2: [xy-x x^2-y], complete: false
+	// Gröbner basis truncated at degree 4: [y^2-y yx-x xy-x x^2-y], complete: true
+}
+
 func ExampleParse() {
 	pStr := "-x^2y^3 + 5/3(y-x)x"
 
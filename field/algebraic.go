@@ -0,0 +1,139 @@
+package field
+
+import (
+	"fmt"
+
+	"github.com/fumin/nag"
+)
+
+// An Algebraic is an element in the algebraic extension K[α]/(m(α)), where m is an irreducible polynomial in a single symbol α over the field K.
+// With K being [nag.Rat], this is a number field such as Q(√2), and towers of extensions such as Q(√2, i) are obtained by nesting, i.e. Algebraic[*Algebraic[*nag.Rat]].
+// Gröbner bases can thus be computed directly over number fields, without adding α as an extra commuting variable to the ideal.
+//
+// An element is represented by its remainder modulo m, which is a polynomial in α of degree less than that of m.
+// Elements of the same field share the polynomial m, which is created once by [NewAlgebraic], together with a [nag.Reducer] for it.
+type Algebraic[K nag.Field[K]] struct {
+	mod *nag.Polynomial[K]
+	red *nag.Reducer[K]
+	p   *nag.Polynomial[K]
+}
+
+// NewAlgebraic returns the additive identity 0 in the field K[α]/(m(α)).
+// NewAlgebraic panics if m is not a polynomial of degree at least one in a single symbol.
+// The irreducibility of m is not checked, but inverting an element fails if m is reducible.
+func NewAlgebraic[K nag.Field[K]](m *nag.Polynomial[K]) *Algebraic[K] {
+	if m.Len() == 0 || len(m.LeadingTerm().Monomial) == 0 {
+		panic(fmt.Sprintf("constant polynomial %v", m))
+	}
+	α := m.LeadingTerm().Monomial[0]
+	for _, w := range m.Terms() {
+		for _, s := range w {
+			if s != α {
+				panic(fmt.Sprintf("polynomial %v is not univariate", m))
+			}
+		}
+	}
+	mod := clone(m)
+	return &Algebraic[K]{mod: mod, red: nag.NewReducer([]*nag.Polynomial[K]{mod}), p: clone(poly0(mod))}
+}
+
+// SetPolynomial sets z to the value of p at α and returns z.
+// The polynomial p must contain only the symbol α of the field.
+func (z *Algebraic[K]) SetPolynomial(p *nag.Polynomial[K]) *Algebraic[K] {
+	α := z.mod.LeadingTerm().Monomial[0]
+	for _, w := range p.Terms() {
+		for _, s := range w {
+			if s != α {
+				panic(fmt.Sprintf("polynomial %v contains symbols other than %s", p, z.mod.SymbolStringer(α)))
+			}
+		}
+	}
+	z.p = z.red.NormalForm(p)
+	return z
+}
+
+// Polynomial returns the polynomial in α of degree less than that of m, which represents x.
+func (x *Algebraic[K]) Polynomial() *nag.Polynomial[K] {
+	return clone(x.p)
+}
+
+// NewZero returns the additive identity 0.
+func (x *Algebraic[K]) NewZero() *Algebraic[K] {
+	return &Algebraic[K]{mod: x.mod, red: x.red, p: clone(poly0(x.mod))}
+}
+
+// NewOne returns the multiplicative identity 1.
+func (x *Algebraic[K]) NewOne() *Algebraic[K] {
+	return &Algebraic[K]{mod: x.mod, red: x.red, p: clone(poly1(x.mod))}
+}
+
+// Equal reports whether x and y are equal.
+func (x *Algebraic[K]) Equal(y *Algebraic[K]) bool {
+	if x.mod != y.mod && !x.mod.Equal(y.mod) {
+		return false
+	}
+	return x.p.Equal(y.p)
+}
+
+// Add sets z to the sum x+y and returns z.
+func (z *Algebraic[K]) Add(x, y *Algebraic[K]) *Algebraic[K] {
+	p := clone(x.p)
+	z.mod, z.red, z.p = x.mod, x.red, p.Add(p, y.p)
+	return z
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *Algebraic[K]) Sub(x, y *Algebraic[K]) *Algebraic[K] {
+	k := x.mod.Field()
+	neg1 := k.NewZero()
+	neg1 = neg1.Sub(neg1, k.NewOne())
+	p := clone(x.p)
+	z.mod, z.red, z.p = x.mod, x.red, p.Add(p, mulScalar(clone(y.p), neg1))
+	return z
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *Algebraic[K]) Mul(x, y *Algebraic[K]) *Algebraic[K] {
+	p := clone(poly0(x.mod))
+	p.Mul(x.p, y.p)
+	z.mod, z.red, z.p = x.mod, x.red, x.red.NormalForm(p)
+	return z
+}
+
+// Div sets z to the quotient x/y and returns z.
+func (z *Algebraic[K]) Div(x, y *Algebraic[K]) *Algebraic[K] {
+	yInv := y.NewZero().Inv(y)
+	z.Mul(x, yInv)
+	return z
+}
+
+// Inv sets z to 1/x and returns z.
+// The inverse is computed by the extended Euclidean algorithm.
+func (z *Algebraic[K]) Inv(x *Algebraic[K]) *Algebraic[K] {
+	if x.p.Len() == 0 {
+		panic("division by zero")
+	}
+	v := inverse(clone(x.p), clone(x.mod))
+	if v == nil {
+		panic(fmt.Sprintf("inverse of %v does not exist, since %v is reducible", x, x.mod))
+	}
+	z.mod, z.red, z.p = x.mod, x.red, x.red.NormalForm(v)
+	return z
+}
+
+// String returns the polynomial in α representing x.
+// Nonconstant elements are enclosed in parentheses, so that they print unambiguously as coefficients of polynomials.
+func (x *Algebraic[K]) String() string {
+	if x.p.Len() == 0 || (x.p.Len() == 1 && len(x.p.LeadingTerm().Monomial) == 0) {
+		return x.p.String()
+	}
+	return "(" + x.p.String() + ")"
+}
+
+// clone returns a copy of x, which unlike [nag.Polynomial.Set], does not share the field of x.
+// Since polynomials use their field for intermediate results, this allows elements sharing the same polynomial m to be used concurrently.
+func clone[K nag.Field[K]](x *nag.Polynomial[K]) *nag.Polynomial[K] {
+	y := nag.NewPolynomial(x.Field().NewZero(), x.Order())
+	y.SymbolStringer = x.SymbolStringer
+	return y.Add(y, x)
+}
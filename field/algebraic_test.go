@@ -0,0 +1,171 @@
+package field
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/fumin/nag"
+)
+
+func TestAlgebraic(t *testing.T) {
+	tests := []struct {
+		m string
+		x string
+		y string
+		// sum, diff, prod and quo are x+y, x-y, x*y and x/y.
+		sum  string
+		diff string
+		prod string
+		quo  string
+	}{
+		{m: "a^2-2", x: "a+1", y: "a-1", sum: "(2a)", diff: "2", prod: "1", quo: "(2a+3)"},
+		{m: "a^2+1", x: "a", y: "a", sum: "(2a)", diff: "0", prod: "-1", quo: "1"},
+		{m: "a^3-2", x: "a^4", y: "a^2", sum: "(a^2+2a)", diff: "(-a^2+2a)", prod: "4", quo: "(a^2)"},
+		{m: "a^3-a-1", x: "a^2+1", y: "a", sum: "(a^2+a+1)", diff: "(a^2-a+1)", prod: "(2a+1)", quo: "(a^2+a-1)"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"a": 0}
+			k := NewAlgebraic(parseRat(variables, test.m))
+			x := k.NewZero().SetPolynomial(parseRat(variables, test.x))
+			y := k.NewZero().SetPolynomial(parseRat(variables, test.y))
+			if z := k.NewZero().Add(x, y); z.String() != test.sum {
+				t.Errorf("Add(%v %v): got %v want %v", x, y, z, test.sum)
+			}
+			if z := k.NewZero().Sub(x, y); z.String() != test.diff {
+				t.Errorf("Sub(%v %v): got %v want %v", x, y, z, test.diff)
+			}
+			if z := k.NewZero().Mul(x, y); z.String() != test.prod {
+				t.Errorf("Mul(%v %v): got %v want %v", x, y, z, test.prod)
+			}
+			if z := k.NewZero().Div(x, y); z.String() != test.quo {
+				t.Errorf("Div(%v %v): got %v want %v", x, y, z, test.quo)
+			}
+			if z := k.NewZero().Mul(y, k.NewZero().Inv(y)); !z.Equal(k.NewOne()) {
+				t.Errorf("Mul(%v, Inv(%v)): got %v want 1", y, y, z)
+			}
+		})
+	}
+}
+
+func TestAlgebraicTower(t *testing.T) {
+	// Construct Q(√2, i) as Q(√2)[i]/(i^2+1).
+	variables := map[string]nag.Symbol{"s": 0}
+	qs := NewAlgebraic(parseRat(variables, "s^2-2"))
+	sqrt2 := qs.NewZero().SetPolynomial(parseRat(variables, "s"))
+	one := qs.NewOne()
+	m := nag.NewPolynomial(qs, nag.Deglex,
+		nag.PolynomialTerm[*Algebraic[*nag.Rat]]{Coefficient: one, Monomial: nag.Monomial{0, 0}},
+		nag.PolynomialTerm[*Algebraic[*nag.Rat]]{Coefficient: one})
+	m.SymbolStringer = func(nag.Symbol) string { return "i" }
+	k := NewAlgebraic(m)
+
+	// α = √2 + i is a root of x^4 - 2x^2 + 9.
+	α := k.NewZero().SetPolynomial(nag.NewPolynomial(qs, nag.Deglex,
+		nag.PolynomialTerm[*Algebraic[*nag.Rat]]{Coefficient: one, Monomial: nag.Monomial{0}},
+		nag.PolynomialTerm[*Algebraic[*nag.Rat]]{Coefficient: sqrt2}))
+	α2 := k.NewZero().Mul(α, α)
+	α4 := k.NewZero().Mul(α2, α2)
+	nine := k.NewZero().SetPolynomial(nag.NewPolynomial(qs, nag.Deglex,
+		nag.PolynomialTerm[*Algebraic[*nag.Rat]]{Coefficient: qs.NewZero().SetPolynomial(parseRat(variables, "9"))}))
+	z := k.NewZero().Sub(α4, α2)
+	z.Sub(z, α2)
+	z.Add(z, nine)
+	if !z.Equal(k.NewZero()) {
+		t.Errorf("α^4-2α^2+9: got %v want 0", z)
+	}
+	if want := "((2s)i+1)"; α2.String() != want {
+		t.Errorf("α^2: got %v want %v", α2, want)
+	}
+
+	if z := k.NewZero().Mul(α, k.NewZero().Inv(α)); !z.Equal(k.NewOne()) {
+		t.Errorf("Mul(%v, Inv(%v)): got %v want 1", α, α, z)
+	}
+}
+
+func TestAlgebraicBuchberger(t *testing.T) {
+	tests := []struct {
+		m     string
+		ideal []string
+		basis []string
+	}{
+		{
+			m:     "s^2-2",
+			ideal: []string{"x^2-2"},
+			basis: []string{"x^2-2"},
+		},
+		{
+			// Since yx = y^3 = xy, the relation yx = √2xy implies xy = 0.
+			m:     "s^2-2",
+			ideal: []string{"yx-sxy", "y^2-x"},
+			basis: []string{"x^2", "xy", "yx", "y^2-x"},
+		},
+		{
+			// Since x^2y = ixyx = -yx^2, the relation x^2 = -1 implies y = 0.
+			m:     "s^2+1",
+			ideal: []string{"xy-syx", "x^2+1"},
+			basis: []string{"y", "x^2+1"},
+		},
+		{
+			// Since y^3 = ixy = iyx, x and y commute.
+			m:     "s^2+1",
+			ideal: []string{"x^2+1", "y^2-sx"},
+			basis: []string{"x^2+1", "yx-xy", "y^2+(-s)x"},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"s": 0, "x": 1, "y": 2}
+			k := NewAlgebraic(parseRat(variables, test.m))
+			ideal := make([]*nag.Polynomial[*Algebraic[*nag.Rat]], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = parseAlgebraic(k, variables, s)
+			}
+
+			basis, complete := nag.Buchberger(ideal, 50)
+			if !complete {
+				t.Fatalf("incomplete")
+			}
+			got := make([]string, len(basis))
+			for j, b := range basis {
+				got[j] = b.String()
+			}
+			if !slices.Equal(got, test.basis) {
+				t.Errorf("got %v want %v", got, test.basis)
+			}
+		})
+	}
+}
+
+func parseRat(variables map[string]nag.Symbol, input string) *nag.Polynomial[*nag.Rat] {
+	p, err := nag.Parse(variables, nag.Deglex, input)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// parseAlgebraic parses input over the rationals, and moves the symbol of k from the monomials into the coefficients.
+func parseAlgebraic(k *Algebraic[*nag.Rat], variables map[string]nag.Symbol, input string) *nag.Polynomial[*Algebraic[*nag.Rat]] {
+	α := k.mod.LeadingTerm().Monomial[0]
+	rp := parseRat(variables, input)
+	p := nag.NewPolynomial(k.NewZero(), rp.Order())
+	p.SymbolStringer = rp.SymbolStringer
+	for c, w := range rp.Terms() {
+		var cw nag.Monomial
+		for _, s := range w {
+			if s == α {
+				cw = append(cw, α)
+			}
+		}
+		w = slices.DeleteFunc(slices.Clone(w), func(s nag.Symbol) bool { return s == α })
+		ck := k.NewZero().SetPolynomial(nag.NewPolynomial(c.NewZero(), nag.Deglex, nag.PolynomialTerm[*nag.Rat]{Coefficient: c, Monomial: cw}))
+		p.Add(p, nag.NewPolynomial(k.NewZero(), rp.Order(), nag.PolynomialTerm[*Algebraic[*nag.Rat]]{Coefficient: ck, Monomial: w}))
+	}
+	return p
+}
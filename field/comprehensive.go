@@ -0,0 +1,150 @@
+package field
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/fumin/nag"
+)
+
+// A Branch is a case of a comprehensive Gröbner system, which consists of conditions on the parameters and the Gröbner basis under those conditions.
+type Branch[K nag.Field[K]] struct {
+	// Zero are the polynomials in the parameters that vanish.
+	Zero []*nag.Polynomial[K]
+	// NonZero are the polynomials in the parameters that do not vanish.
+	NonZero []*nag.Polynomial[K]
+	// Basis is the Gröbner basis for the values of the parameters satisfying the conditions.
+	// The numerators of its coefficients are reduced modulo Zero.
+	Basis []*nag.Polynomial[*RationalFunc[K]]
+}
+
+// ComprehensiveBuchberger returns a comprehensive Gröbner system of the ideal g, whose coefficients are rational functions in parameters.
+// The basis computed by [nag.Buchberger] over the rational functions is only valid for values of the parameters, where the coefficients inverted during the computation do not vanish.
+// ComprehensiveBuchberger records these coefficients, and splits the parameter space into branches where each of them either vanishes or not.
+// The Gröbner basis of each branch is then recomputed under its conditions, recursively until no new conditions arise.
+//
+// The first branch is the generic case, where all recorded coefficients do not vanish.
+// For every value of the parameters where the denominators of g do not vanish, there is exactly one branch whose conditions are satisfied, and the Gröbner basis of g specialized to that value is obtained by specializing the basis of the branch.
+// However, the conditions of a branch are not simplified, and some branches may have no values satisfying them.
+// The returned system is complete if all bases are complete.
+// For more details, please see Weispfenning.
+//
+// Weispfenning, Volker. "Comprehensive Gröbner bases." Journal of Symbolic Computation 14.1 (1992): 1-29.
+func ComprehensiveBuchberger[K nag.Field[K]](ctx context.Context, g []*nag.Polynomial[*RationalFunc[K]], maxIter int, opts *nag.Options) (branches []Branch[K], complete bool, err error) {
+	if len(g) == 0 {
+		return nil, true, nil
+	}
+	f := g[0].Field().f
+
+	// The denominators of g are assumed to not vanish.
+	divisors := &divisorSet[K]{}
+	for _, p := range g {
+		for c := range p.Terms() {
+			divisors.add(c.den)
+		}
+	}
+	return comprehensive(ctx, g, f, nil, divisors.p, maxIter, opts)
+}
+
+// comprehensive returns the branches of the comprehensive Gröbner system of g, whose parameters satisfy the conditions zero and nonZero.
+func comprehensive[K nag.Field[K]](ctx context.Context, g []*nag.Polynomial[*RationalFunc[K]], root *rationalFuncField[K], zero, nonZero []cpoly[K], maxIter int, opts *nag.Options) ([]Branch[K], bool, error) {
+	f := &rationalFuncField[K]{k: root.k, params: root.params, divisors: &divisorSet[K]{}}
+	if len(zero) != 0 {
+		var complete bool
+		var err error
+		f.zero, complete, err = nag.BuchbergerContext(ctx, commutative(root, zero), math.MaxInt)
+		if err != nil {
+			return nil, false, err
+		}
+		if !complete {
+			return nil, false, nil
+		}
+		f.reducer = nag.NewReducer(f.zero)
+		// The conditions are inconsistent if the vanishing polynomials generate the unit ideal, or a polynomial assumed to not vanish.
+		for _, z := range f.zero {
+			if z.Len() == 1 && len(z.LeadingTerm().Monomial) == 0 {
+				return nil, true, nil
+			}
+		}
+		for _, n := range nonZero {
+			if len(f.reduce(n)) == 0 {
+				return nil, true, nil
+			}
+		}
+	}
+
+	// Compute the basis of g under the conditions.
+	gf := make([]*nag.Polynomial[*RationalFunc[K]], 0, len(g))
+	for _, p := range g {
+		q := nag.NewPolynomial(&RationalFunc[K]{f: f, den: f.one()}, p.Order())
+		q.SymbolStringer = p.SymbolStringer
+		for c, w := range p.Terms() {
+			fc := (&RationalFunc[K]{}).set(f, c.num, c.den)
+			q.Add(q, nag.NewPolynomial(fc.NewZero(), p.Order(), nag.PolynomialTerm[*RationalFunc[K]]{Coefficient: fc, Monomial: w}))
+		}
+		if q.Len() != 0 {
+			gf = append(gf, q)
+		}
+	}
+	basis, complete, _, err := nag.BuchbergerWithOptions(ctx, gf, maxIter, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Collect the new conditions, after removing the factors already assumed to not vanish.
+	var split []cpoly[K]
+	for _, p := range f.divisors.p {
+		for _, n := range nonZero {
+			for {
+				d := gcdCommutative(p, n, 0)
+				if d.isConstant() {
+					break
+				}
+				p = p.divideExact(d)
+			}
+		}
+		if p.isConstant() || len(f.reduce(p)) == 0 || slices.ContainsFunc(split, p.monic().equal) {
+			continue
+		}
+		split = append(split, p.monic())
+	}
+
+	generic := append(slices.Clone(nonZero), split...)
+	branches := []Branch[K]{{Zero: polynomials(root, zero), NonZero: polynomials(root, generic), Basis: basis}}
+	// The i'th branch assumes that split[i] vanishes, whereas the preceding ones do not.
+	for i, p := range split {
+		bs, c, err := comprehensive(ctx, g, root, append(slices.Clone(zero), p), generic[:len(nonZero)+i], maxIter, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		branches = append(branches, bs...)
+		complete = complete && c
+	}
+	return branches, complete, nil
+}
+
+// commutative returns the polynomials in p, together with the commutators of the parameters, which generate the same ideal as p in the commutative polynomial ring.
+func commutative[K nag.Field[K]](f *rationalFuncField[K], p []cpoly[K]) []*nag.Polynomial[K] {
+	g := polynomials(f, p)
+	k, one := len(f.params), f.k.NewOne()
+	neg1 := f.k.NewZero().Sub(f.k.NewZero(), one)
+	for i := range k {
+		for j := range i {
+			x, y := nag.Symbol(i), nag.Symbol(j)
+			c := nag.NewPolynomial(f.k.NewZero(), nag.Deglex,
+				nag.PolynomialTerm[K]{Coefficient: one, Monomial: nag.Monomial{x, y}},
+				nag.PolynomialTerm[K]{Coefficient: neg1, Monomial: nag.Monomial{y, x}})
+			g = append(g, c)
+		}
+	}
+	return g
+}
+
+func polynomials[K nag.Field[K]](f *rationalFuncField[K], p []cpoly[K]) []*nag.Polynomial[K] {
+	g := make([]*nag.Polynomial[K], 0, len(p))
+	for _, q := range p {
+		g = append(g, f.polynomial(q))
+	}
+	return g
+}
@@ -0,0 +1,154 @@
+package field
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/fumin/nag"
+)
+
+func TestComprehensiveBuchberger(t *testing.T) {
+	type branch struct {
+		zero    []string
+		nonZero []string
+		basis   []string
+	}
+	tests := []struct {
+		ideal    []string
+		branches []branch
+	}{
+		{
+			ideal: []string{"ba-λab", "b^2-1"},
+			branches: []branch{
+				{zero: []string{}, nonZero: []string{"λ^2-1"}, basis: []string{"a", "b^2-1"}},
+				{zero: []string{"λ^2-1"}, nonZero: []string{}, basis: []string{"ba+(-λ)ab", "b^2-1"}},
+			},
+		},
+		{
+			ideal: []string{"λa^2-b", "a^2-μb"},
+			branches: []branch{
+				{zero: []string{}, nonZero: []string{"λμ-1"}, basis: []string{"b", "a^2"}},
+				{zero: []string{"λμ-1"}, nonZero: []string{"μ"}, basis: []string{"a^2+(-μ)b", "ba-ab"}},
+			},
+		},
+		{
+			ideal: []string{"a^2-λb", "(1-λ)ab+λba-μ"},
+			branches: []branch{
+				{zero: []string{}, nonZero: []string{"λ", "λ-1/2"}, basis: []string{"a^2+(-λ)b", "ab+(-μ)", "ba+(-μ)", "b^2+(-μ/λ)a"}},
+				{zero: []string{"λ"}, nonZero: []string{"μ"}, basis: []string{"1"}},
+				{zero: []string{"λ", "μ"}, nonZero: []string{}, basis: []string{"a^2", "ab"}},
+				{zero: []string{"λ-1/2"}, nonZero: []string{"λ"}, basis: []string{"a^2-1/2b", "ab+(-μ)", "ba+(-μ)", "b^2+(-2μ)a"}},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"λ": 0, "μ": 3, "a": 1, "b": 2}
+			k := NewRationalFunc(nag.NewRat(0, 1), "λ", "μ")
+			ideal := make([]*nag.Polynomial[*RationalFunc[*nag.Rat]], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = k.Parametrize(parseRat(variables, s), 0, 3)
+			}
+
+			branches, complete, err := ComprehensiveBuchberger(context.Background(), ideal, 50, nil)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if !complete {
+				t.Fatalf("incomplete")
+			}
+			got := make([]branch, len(branches))
+			for j, b := range branches {
+				got[j] = branch{zero: polynomialStrings(b.Zero), nonZero: polynomialStrings(b.NonZero), basis: polynomialStrings(b.Basis)}
+			}
+			if !slices.EqualFunc(got, test.branches, func(x, y branch) bool {
+				return slices.Equal(x.zero, y.zero) && slices.Equal(x.nonZero, y.nonZero) && slices.Equal(x.basis, y.basis)
+			}) {
+				t.Fatalf("got %v want %v", got, test.branches)
+			}
+
+			// Check that for each value of the parameters, the basis of its branch is the Gröbner basis of the specialized ideal.
+			values := []*nag.Rat{nag.NewRat(0, 1), nag.NewRat(1, 2), nag.NewRat(1, 1), nag.NewRat(-1, 1), nag.NewRat(2, 1)}
+			for _, λ := range values {
+				for _, μ := range values {
+					point := []*nag.Rat{λ, μ}
+					var matched []Branch[*nag.Rat]
+					for _, b := range branches {
+						if satisfies(b, point) {
+							matched = append(matched, b)
+						}
+					}
+					if len(matched) != 1 {
+						t.Fatalf("λ=%v μ=%v: %d branches", λ, μ, len(matched))
+					}
+
+					specialized := make([]*nag.Polynomial[*nag.Rat], len(ideal))
+					for j, p := range ideal {
+						specialized[j] = specialize(p, point)
+					}
+					want, _ := nag.Buchberger(specialized, 50)
+					gotBasis := make([]*nag.Polynomial[*nag.Rat], len(matched[0].Basis))
+					for j, p := range matched[0].Basis {
+						gotBasis[j] = specialize(p, point)
+					}
+					if g, w := slices.Sorted(slices.Values(polynomialStrings(gotBasis))), slices.Sorted(slices.Values(polynomialStrings(want))); !slices.Equal(g, w) {
+						t.Errorf("λ=%v μ=%v: got %v want %v", λ, μ, g, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+// satisfies reports whether the values of the parameters at point satisfy the conditions of b.
+func satisfies(b Branch[*nag.Rat], point []*nag.Rat) bool {
+	for _, z := range b.Zero {
+		if evaluate(z, point).Sign() != 0 {
+			return false
+		}
+	}
+	for _, n := range b.NonZero {
+		if evaluate(n, point).Sign() == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate returns the value of the polynomial p in the parameters at point, where the i'th parameter is the symbol len(point)-1-i.
+func evaluate(p *nag.Polynomial[*nag.Rat], point []*nag.Rat) *big.Rat {
+	v := new(big.Rat)
+	for c, w := range p.Terms() {
+		t := new(big.Rat).Set(c.Rat)
+		for _, s := range w {
+			t.Mul(t, point[len(point)-1-int(s)].Rat)
+		}
+		v.Add(v, t)
+	}
+	return v
+}
+
+// specialize returns p with the parameters in its coefficients replaced by the values at point.
+func specialize(p *nag.Polynomial[*RationalFunc[*nag.Rat]], point []*nag.Rat) *nag.Polynomial[*nag.Rat] {
+	z := nag.NewPolynomial(nag.NewRat(0, 1), p.Order())
+	z.SymbolStringer = p.SymbolStringer
+	for c, w := range p.Terms() {
+		num, den := evaluate(c.f.polynomial(c.num), point), evaluate(c.f.polynomial(c.den), point)
+		sc := &nag.Rat{Rat: new(big.Rat).Quo(num, den)}
+		z.Add(z, nag.NewPolynomial(nag.NewRat(0, 1), p.Order(), nag.PolynomialTerm[*nag.Rat]{Coefficient: sc, Monomial: w}))
+	}
+	return z
+}
+
+func polynomialStrings[K nag.Field[K]](p []*nag.Polynomial[K]) []string {
+	s := make([]string, len(p))
+	for i, q := range p {
+		s[i] = q.String()
+	}
+	return s
+}
@@ -2,10 +2,12 @@ package field_test
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"math/big"
 	"slices"
 
+	"github.com/fumin/nag"
 	"github.com/fumin/nag/field"
 )
 
@@ -61,3 +63,117 @@ func ExampleFactor() {
 	// x^2+2x+21888242871839275222246405745257275088548364400416034343698204186575808495609
 	// == [{x+4 1} {x+21888242871839275222246405745257275088548364400416034343698204186575808495615 1}]
 }
+
+func ExampleFp() {
+	// This example computes a Gröbner basis over the prime field GF(7).
+	// Over the rationals, the relations below imply a^3 = 8a^3 and hence a^3 = 0.
+	// However, 8 = 1 in GF(7), and a and b merely commute.
+	// The relations are parsed over the rationals, and then cast to GF(7).
+	variables := map[string]nag.Symbol{"a": 1, "b": 2}
+	k := field.NewFp(7)
+	var ideal []*nag.Polynomial[*field.Fp]
+	for _, rule := range []string{"ab - 8ba", "a^2 - b"} {
+		rp, _ := nag.Parse(variables, nag.Deglex, rule)
+		p := nag.NewPolynomial(k, rp.Order())
+		p.SymbolStringer = rp.SymbolStringer
+		for c, w := range rp.Terms() {
+			ck := k.NewZero().Div(k.Ith(c.Num()), k.Ith(c.Denom()))
+			p.Add(p, nag.NewPolynomial(k, p.Order(), nag.PolynomialTerm[*field.Fp]{Coefficient: ck, Monomial: w}))
+		}
+		ideal = append(ideal, p)
+	}
+
+	basis, _ := nag.Buchberger(ideal, 50)
+	for _, b := range basis {
+		fmt.Println(b)
+	}
+
+	// Output:
+	// a^2+6b
+	// ba+6ab
+}
+
+func ExampleAlgebraic() {
+	// This example computes a Gröbner basis over the number field Q(√2).
+	// Unlike Example_minimal_polynomial of package nag, √2 is a coefficient instead of an extra commuting variable in the ideal.
+	vars := map[string]nag.Symbol{"s": 0}
+	m, _ := nag.Parse(vars, nag.Deglex, "s^2 - 2")
+	k := field.NewAlgebraic(m)
+	s, _ := nag.Parse(vars, nag.Deglex, "s")
+	sqrt2 := k.NewZero().SetPolynomial(s)
+	one, two := k.NewOne(), k.NewZero().Add(k.NewOne(), k.NewOne())
+	neg := func(c *field.Algebraic[*nag.Rat]) *field.Algebraic[*nag.Rat] { return k.NewZero().Sub(k.NewZero(), c) }
+
+	// Create the ideal a^2 - √2b, ab - ba, ab - 2 over Q(√2).
+	a, b := nag.Symbol(1), nag.Symbol(2)
+	type term = nag.PolynomialTerm[*field.Algebraic[*nag.Rat]]
+	ideal := []*nag.Polynomial[*field.Algebraic[*nag.Rat]]{
+		nag.NewPolynomial(k, nag.Deglex, term{Coefficient: one, Monomial: nag.Monomial{a, a}}, term{Coefficient: neg(sqrt2), Monomial: nag.Monomial{b}}),
+		nag.NewPolynomial(k, nag.Deglex, term{Coefficient: one, Monomial: nag.Monomial{a, b}}, term{Coefficient: neg(one), Monomial: nag.Monomial{b, a}}),
+		nag.NewPolynomial(k, nag.Deglex, term{Coefficient: one, Monomial: nag.Monomial{a, b}}, term{Coefficient: neg(two)}),
+	}
+
+	// Notice the new relation b^2 = √2a in the Gröbner basis.
+	basis, _ := nag.Buchberger(ideal, 50)
+	for _, g := range basis {
+		fmt.Println(g)
+	}
+
+	// Output:
+	// a^2+(-s)b
+	// ab-2
+	// ba-2
+	// b^2+(-s)a
+}
+
+func ExampleInterval() {
+	// This example computes a Gröbner basis with interval coefficients.
+	// The coefficient 0.1 is not exactly representable in binary, so 10*0.1 - 1 is only known to lie in a small interval around zero.
+	// The resulting basis is correct only if that coefficient is truly zero, which Ambiguous reports.
+	variables := map[string]nag.Symbol{"a": 1, "b": 2}
+	k := field.NewInterval(53)
+	var ideal []*nag.Polynomial[*field.Interval]
+	for _, rule := range []string{"a^2 - 1/10b", "10a^2 - b"} {
+		rp, _ := nag.Parse(variables, nag.Deglex, rule)
+		p := nag.NewPolynomial(k, rp.Order())
+		p.SymbolStringer = rp.SymbolStringer
+		for c, w := range rp.Terms() {
+			ck := k.NewZero().SetRat(c.Rat)
+			p.Add(p, nag.NewPolynomial(k, p.Order(), nag.PolynomialTerm[*field.Interval]{Coefficient: ck, Monomial: w}))
+		}
+		ideal = append(ideal, p)
+	}
+
+	basis, _ := nag.Buchberger(ideal, 50)
+	fmt.Println(basis[0])
+	fmt.Println("ambiguous:", k.Ambiguous())
+
+	// Output:
+	// a^2+[-0.1,-0.1]b
+	// ambiguous: true
+}
+
+func ExampleComprehensiveBuchberger() {
+	// This example computes the Gröbner bases of the ideal
+	//
+	//   ba - λab, b^2 - 1
+	//
+	// for all values of the parameter λ.
+	variables := map[string]nag.Symbol{"λ": 0, "a": 1, "b": 2}
+	k := field.NewRationalFunc(nag.NewRat(0, 1), "λ")
+	var ideal []*nag.Polynomial[*field.RationalFunc[*nag.Rat]]
+	for _, s := range []string{"ba - λab", "b^2 - 1"} {
+		p, _ := nag.Parse(variables, nag.Deglex, s)
+		ideal = append(ideal, k.Parametrize(p, variables["λ"]))
+	}
+
+	// Since b^2a = λ^2ab^2, a vanishes unless λ^2 = 1.
+	branches, _, _ := field.ComprehensiveBuchberger(context.Background(), ideal, 50, nil)
+	for _, b := range branches {
+		fmt.Printf("zero: %v, nonzero: %v, basis: %v\n", b.Zero, b.NonZero, b.Basis)
+	}
+
+	// Output:
+	// zero: [], nonzero: [λ^2-1], basis: [a b^2-1]
+	// zero: [λ^2-1], nonzero: [], basis: [ba+(-λ)ab b^2-1]
+}
@@ -1,4 +1,5 @@
-// Package field implements [finite field] arithmetic.
+// Package field implements [finite field] arithmetic,
+// as well as other coefficient fields for package nag, such as algebraic number fields, rational functions and floating-point numbers.
 //
 // [finite field]: https://en.wikipedia.org/wiki/Finite_field
 package field
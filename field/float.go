@@ -0,0 +1,293 @@
+package field
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync/atomic"
+)
+
+// A Float is a floating-point number of a fixed precision, which approximates a field for numerical exploration.
+// Since rounding errors prevent terms from canceling exactly, Float considers two numbers equal if their difference is within a tolerance tol:
+//
+//	|x - y| <= tol * max(1, |x|, |y|)
+//
+// In particular, a coefficient is considered zero if its absolute value is at most tol.
+// A tolerance that is too small keeps rounding errors as spurious terms, whereas one that is too large discards genuine terms.
+// [Interval] detects the latter case.
+type Float struct {
+	*big.Float
+	tol float64
+}
+
+// NewFloat returns the number x with prec bits of mantissa, and tolerance tol for equality.
+func NewFloat(x float64, prec uint, tol float64) *Float {
+	return &Float{Float: new(big.Float).SetPrec(prec).SetFloat64(x), tol: tol}
+}
+
+// NewZero returns the additive identity 0.
+func (x *Float) NewZero() *Float {
+	return &Float{Float: new(big.Float).SetPrec(x.Prec()), tol: x.tol}
+}
+
+// NewOne returns the multiplicative identity 1.
+func (x *Float) NewOne() *Float {
+	return &Float{Float: new(big.Float).SetPrec(x.Prec()).SetInt64(1), tol: x.tol}
+}
+
+// Equal reports whether x and y are equal within the tolerance of x.
+func (x *Float) Equal(y *Float) bool {
+	diff := new(big.Float).SetPrec(x.Prec()).Sub(x.Float, y.Float)
+	bound := new(big.Float).SetPrec(x.Prec()).SetInt64(1)
+	if ax := new(big.Float).Abs(x.Float); ax.Cmp(bound) > 0 {
+		bound = ax
+	}
+	if ay := new(big.Float).Abs(y.Float); ay.Cmp(bound) > 0 {
+		bound = ay
+	}
+	bound = new(big.Float).SetPrec(x.Prec()).Mul(bound, big.NewFloat(x.tol))
+	return diff.Abs(diff).Cmp(bound) <= 0
+}
+
+// Add sets z to the sum x+y and returns z.
+func (z *Float) Add(x, y *Float) *Float {
+	z.tol = x.tol
+	z.Float.Add(x.Float, y.Float)
+	return z
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *Float) Sub(x, y *Float) *Float {
+	z.tol = x.tol
+	z.Float.Sub(x.Float, y.Float)
+	return z
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *Float) Mul(x, y *Float) *Float {
+	z.tol = x.tol
+	z.Float.Mul(x.Float, y.Float)
+	return z
+}
+
+// Div sets z to the quotient x/y and returns z. If y == 0, Div panics.
+func (z *Float) Div(x, y *Float) *Float {
+	if y.Sign() == 0 {
+		panic("division by zero")
+	}
+	z.tol = x.tol
+	z.Float.Quo(x.Float, y.Float)
+	return z
+}
+
+// Inv sets z to 1/x and returns z. If x == 0, Inv panics.
+func (z *Float) Inv(x *Float) *Float {
+	if x.Sign() == 0 {
+		panic("division by zero")
+	}
+	z.tol = x.tol
+	z.Float.Quo(new(big.Float).SetInt64(1), x.Float)
+	return z
+}
+
+// String returns x with as many significant digits as the tolerance allows.
+func (x *Float) String() string {
+	return x.Text('g', significantDigits(x.tol))
+}
+
+// significantDigits returns the number of decimal digits resolved by the tolerance tol, or -1 for the shortest exact representation if tol is zero.
+func significantDigits(tol float64) int {
+	if tol <= 0 {
+		return -1
+	}
+	return max(1, int(math.Floor(-math.Log10(tol))))
+}
+
+// An Interval is an interval of real numbers, whose endpoints are floating-point numbers of a fixed precision.
+// Arithmetic rounds the endpoints outward, so that the interval always contains the exact result.
+// Interval is thus a numerical field that is aware of its rounding errors.
+//
+// Equal reports whether the difference of two intervals contains zero.
+// A zero test is ambiguous if the difference contains zero, but is not exactly zero.
+// In that case, the true value may be nonzero, and a polynomial computed with intervals may have dropped its true leading term.
+// Such ambiguities are recorded in the field, and reported by [Interval.Ambiguous].
+//
+// For more details, please see Moore et al.
+//
+// Moore, Ramon E., R. Baker Kearfott, and Michael J. Cloud. "Introduction to interval analysis." Society for Industrial and Applied Mathematics, 2009.
+type Interval struct {
+	f      *intervalField
+	lo, hi *big.Float
+	// num and den record that the interval is the quotient num/den.
+	// They allow (x/y)*y to be exactly x, so that the leading terms canceled by division and S-polynomials vanish exactly.
+	// Since endpoints are never modified, sharing them implies the same number.
+	num, den bounds
+}
+
+// bounds are the endpoints of an interval.
+type bounds struct{ lo, hi *big.Float }
+
+// An intervalField holds the precision and ambiguities of intervals, which are shared by all its elements.
+type intervalField struct {
+	prec      uint
+	ambiguous atomic.Bool
+}
+
+// NewInterval returns the additive identity 0 in the field of intervals, whose endpoints have prec bits of mantissa.
+// Elements of the field are obtained by [Interval.SetFloat64] and [Interval.Set].
+func NewInterval(prec uint) *Interval {
+	f := &intervalField{prec: prec}
+	return &Interval{f: f, lo: f.float(0), hi: f.float(0)}
+}
+
+func (f *intervalField) float(x int64) *big.Float {
+	return new(big.Float).SetPrec(f.prec).SetInt64(x)
+}
+
+// Set sets z to the interval [lo, hi] rounded outward, and returns z.
+// Set panics if lo > hi.
+func (z *Interval) Set(lo, hi *big.Float) *Interval {
+	if lo.Cmp(hi) > 0 {
+		panic(fmt.Sprintf("empty interval [%v, %v]", lo, hi))
+	}
+	z.lo = new(big.Float).SetPrec(z.f.prec).SetMode(big.ToNegativeInf).Set(lo)
+	z.hi = new(big.Float).SetPrec(z.f.prec).SetMode(big.ToPositiveInf).Set(hi)
+	z.num, z.den = bounds{}, bounds{}
+	return z
+}
+
+// SetFloat64 sets z to the interval [lo, hi] and returns z.
+func (z *Interval) SetFloat64(lo, hi float64) *Interval {
+	return z.Set(big.NewFloat(lo), big.NewFloat(hi))
+}
+
+// SetRat sets z to the smallest interval containing x and returns z.
+func (z *Interval) SetRat(x *big.Rat) *Interval {
+	z.lo = new(big.Float).SetPrec(z.f.prec).SetMode(big.ToNegativeInf).SetRat(x)
+	z.hi = new(big.Float).SetPrec(z.f.prec).SetMode(big.ToPositiveInf).SetRat(x)
+	z.num, z.den = bounds{}, bounds{}
+	return z
+}
+
+// Bounds returns the endpoints of x.
+func (x *Interval) Bounds() (lo, hi *big.Float) {
+	return new(big.Float).Copy(x.lo), new(big.Float).Copy(x.hi)
+}
+
+// Ambiguous reports whether a zero test among the elements of the field of x has been ambiguous.
+func (x *Interval) Ambiguous() bool {
+	return x.f.ambiguous.Load()
+}
+
+// NewZero returns the additive identity 0.
+func (x *Interval) NewZero() *Interval {
+	return &Interval{f: x.f, lo: x.f.float(0), hi: x.f.float(0)}
+}
+
+// NewOne returns the multiplicative identity 1.
+func (x *Interval) NewOne() *Interval {
+	return &Interval{f: x.f, lo: x.f.float(1), hi: x.f.float(1)}
+}
+
+// Equal reports whether the difference of x and y contains zero.
+// If the difference is not exactly zero, the zero test is recorded as ambiguous.
+func (x *Interval) Equal(y *Interval) bool {
+	d := x.NewZero().Sub(x, y)
+	if d.lo.Sign() > 0 || d.hi.Sign() < 0 {
+		return false
+	}
+	if d.lo.Sign() != 0 || d.hi.Sign() != 0 {
+		x.f.ambiguous.Store(true)
+	}
+	return true
+}
+
+// Add sets z to the sum x+y and returns z.
+func (z *Interval) Add(x, y *Interval) *Interval {
+	lo := x.down().Add(x.lo, y.lo)
+	hi := x.up().Add(x.hi, y.hi)
+	z.f, z.lo, z.hi, z.num, z.den = x.f, lo, hi, bounds{}, bounds{}
+	return z
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *Interval) Sub(x, y *Interval) *Interval {
+	lo := x.down().Sub(x.lo, y.hi)
+	hi := x.up().Sub(x.hi, y.lo)
+	z.f, z.lo, z.hi, z.num, z.den = x.f, lo, hi, bounds{}, bounds{}
+	return z
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *Interval) Mul(x, y *Interval) *Interval {
+	switch {
+	case x.den == y.bounds():
+		z.f, z.lo, z.hi, z.num, z.den = x.f, x.num.lo, x.num.hi, bounds{}, bounds{}
+		return z
+	case y.den == x.bounds():
+		z.f, z.lo, z.hi, z.num, z.den = x.f, y.num.lo, y.num.hi, bounds{}, bounds{}
+		return z
+	}
+	var lo, hi *big.Float
+	for _, a := range []*big.Float{x.lo, x.hi} {
+		for _, b := range []*big.Float{y.lo, y.hi} {
+			if l := x.down().Mul(a, b); lo == nil || l.Cmp(lo) < 0 {
+				lo = l
+			}
+			if h := x.up().Mul(a, b); hi == nil || h.Cmp(hi) > 0 {
+				hi = h
+			}
+		}
+	}
+	z.f, z.lo, z.hi, z.num, z.den = x.f, lo, hi, bounds{}, bounds{}
+	return z
+}
+
+// Div sets z to the quotient x/y and returns z.
+// Div panics if y contains zero.
+func (z *Interval) Div(x, y *Interval) *Interval {
+	num, den := x.bounds(), y.bounds()
+	if num == den {
+		z.f, z.lo, z.hi, z.num, z.den = x.f, x.f.float(1), x.f.float(1), bounds{}, bounds{}
+		return z
+	}
+	z.Mul(x, y.NewZero().Inv(y))
+	z.num, z.den = num, den
+	return z
+}
+
+// Inv sets z to 1/x and returns z.
+// Inv panics if x contains zero.
+func (z *Interval) Inv(x *Interval) *Interval {
+	if x.lo.Sign() <= 0 && x.hi.Sign() >= 0 {
+		panic(fmt.Sprintf("division by interval %v containing zero", x))
+	}
+	one := x.f.float(1)
+	lo := x.down().Quo(one, x.hi)
+	hi := x.up().Quo(one, x.lo)
+	z.f, z.lo, z.hi, z.num, z.den = x.f, lo, hi, bounds{one, one}, x.bounds()
+	return z
+}
+
+// String returns x in the form "[lo,hi]", or as a single number if lo == hi.
+// The endpoints are formatted with 10 significant digits.
+func (x *Interval) String() string {
+	if x.lo.Cmp(x.hi) == 0 {
+		return x.lo.Text('g', 10)
+	}
+	return "[" + x.lo.Text('g', 10) + "," + x.hi.Text('g', 10) + "]"
+}
+
+func (x *Interval) bounds() bounds {
+	return bounds{lo: x.lo, hi: x.hi}
+}
+
+// down returns a number that rounds toward negative infinity.
+func (x *Interval) down() *big.Float {
+	return new(big.Float).SetPrec(x.f.prec).SetMode(big.ToNegativeInf)
+}
+
+// up returns a number that rounds toward positive infinity.
+func (x *Interval) up() *big.Float {
+	return new(big.Float).SetPrec(x.f.prec).SetMode(big.ToPositiveInf)
+}
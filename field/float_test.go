@@ -0,0 +1,238 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/fumin/nag"
+)
+
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		x   float64
+		y   float64
+		tol float64
+		// sum, diff, prod and quo are x+y, x-y, x*y and x/y.
+		sum   string
+		diff  string
+		prod  string
+		quo   string
+		equal bool
+	}{
+		{x: 1, y: 3, tol: 1e-12, sum: "4", diff: "-2", prod: "3", quo: "0.333333333333", equal: false},
+		{x: 0.1, y: 0.2, tol: 1e-12, sum: "0.3", diff: "-0.1", prod: "0.02", quo: "0.5", equal: false},
+		{x: 1, y: 1 + 0x1p-45, tol: 1e-12, sum: "2", diff: "-2.84217094304e-14", prod: "1", quo: "1", equal: true},
+		{x: 1e-13, y: 0, tol: 1e-12, sum: "1e-13", diff: "1e-13", prod: "0", quo: "", equal: true},
+		{x: 0.5, y: 0.25, tol: 0, sum: "0.75", diff: "0.25", prod: "0.125", quo: "2", equal: false},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			x, y := NewFloat(test.x, 64, test.tol), NewFloat(test.y, 64, test.tol)
+			if z := x.NewZero().Add(x, y); z.String() != test.sum {
+				t.Errorf("Add(%v %v): got %v want %v", x, y, z, test.sum)
+			}
+			if z := x.NewZero().Sub(x, y); z.String() != test.diff {
+				t.Errorf("Sub(%v %v): got %v want %v", x, y, z, test.diff)
+			}
+			if z := x.NewZero().Mul(x, y); z.String() != test.prod {
+				t.Errorf("Mul(%v %v): got %v want %v", x, y, z, test.prod)
+			}
+			if test.y != 0 {
+				if z := x.NewZero().Div(x, y); z.String() != test.quo {
+					t.Errorf("Div(%v %v): got %v want %v", x, y, z, test.quo)
+				}
+				if z := x.NewZero().Mul(y, x.NewZero().Inv(y)); !z.Equal(x.NewOne()) {
+					t.Errorf("Mul(%v, Inv(%v)): got %v want 1", y, y, z)
+				}
+			}
+			if eq := x.Equal(y); eq != test.equal {
+				t.Errorf("Equal(%v, %v): got %v want %v", x, y, eq, test.equal)
+			}
+		})
+	}
+}
+
+func TestFloatBuchberger(t *testing.T) {
+	tests := []struct {
+		ideal []string
+	}{
+		{ideal: []string{"ab-8ba", "a^2-b"}},
+		{ideal: []string{"a^2-1/3b", "3ab-ba"}},
+		// The coefficients 1/3 and 1/7 are inexact, so the leading terms of S-polynomials only cancel within the tolerance.
+		{ideal: []string{"3ab-b^2-1/3", "7a^2-1/7b"}},
+		{ideal: []string{"a^3-2ab", "a^2b-2b^2+a"}},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"a": 1, "b": 2}
+			k := NewFloat(0, 64, 1e-10)
+			ratIdeal := make([]*nag.Polynomial[*nag.Rat], len(test.ideal))
+			ideal := make([]*nag.Polynomial[*Float], len(test.ideal))
+			for j, s := range test.ideal {
+				ratIdeal[j] = parseRat(variables, s)
+				ideal[j] = parseFloat(k, variables, s)
+			}
+
+			ratBasis, complete := nag.Buchberger(ratIdeal, 50)
+			if !complete {
+				t.Fatalf("incomplete")
+			}
+			want := make([]string, len(ratBasis))
+			for j, b := range ratBasis {
+				want[j] = parseFloat(k, variables, b.String()).String()
+			}
+			basis, complete := nag.Buchberger(ideal, 50)
+			if !complete {
+				t.Fatalf("incomplete")
+			}
+			got := make([]string, len(basis))
+			for j, b := range basis {
+				got[j] = b.String()
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		x [2]float64
+		y [2]float64
+		// sum, diff, prod and quo are x+y, x-y, x*y and x/y.
+		sum   string
+		diff  string
+		prod  string
+		quo   string
+		equal bool
+	}{
+		{x: [2]float64{1, 2}, y: [2]float64{3, 4}, sum: "[4,6]", diff: "[-3,-1]", prod: "[3,8]", quo: "[0.25,0.6666666667]", equal: false},
+		{x: [2]float64{-1, 2}, y: [2]float64{-3, -2}, sum: "[-4,0]", diff: "[1,5]", prod: "[-6,3]", quo: "[-1,0.5]", equal: false},
+		{x: [2]float64{1, 2}, y: [2]float64{1.5, 3}, sum: "[2.5,5]", diff: "[-2,0.5]", prod: "[1.5,6]", quo: "[0.3333333333,1.333333333]", equal: true},
+		{x: [2]float64{5, 5}, y: [2]float64{2, 2}, sum: "7", diff: "3", prod: "10", quo: "2.5", equal: false},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			k := NewInterval(64)
+			x := k.NewZero().SetFloat64(test.x[0], test.x[1])
+			y := k.NewZero().SetFloat64(test.y[0], test.y[1])
+			if z := k.NewZero().Add(x, y); z.String() != test.sum {
+				t.Errorf("Add(%v %v): got %v want %v", x, y, z, test.sum)
+			}
+			if z := k.NewZero().Sub(x, y); z.String() != test.diff {
+				t.Errorf("Sub(%v %v): got %v want %v", x, y, z, test.diff)
+			}
+			if z := k.NewZero().Mul(x, y); z.String() != test.prod {
+				t.Errorf("Mul(%v %v): got %v want %v", x, y, z, test.prod)
+			}
+			if z := k.NewZero().Div(x, y); z.String() != test.quo {
+				t.Errorf("Div(%v %v): got %v want %v", x, y, z, test.quo)
+			}
+			if z := k.NewZero().Mul(y, k.NewZero().Inv(y)); !z.Equal(k.NewOne()) {
+				t.Errorf("Mul(%v, Inv(%v)): got %v want 1", y, y, z)
+			}
+			if eq := x.Equal(y); eq != test.equal {
+				t.Errorf("Equal(%v, %v): got %v want %v", x, y, eq, test.equal)
+			}
+		})
+	}
+}
+
+func TestIntervalEnclosure(t *testing.T) {
+	// Sum 1/n for n = 1..50, whose endpoints must enclose the exact sum.
+	k := NewInterval(24)
+	sum, exact := k.NewZero(), new(big.Rat)
+	for n := int64(1); n <= 50; n++ {
+		r := big.NewRat(1, n)
+		sum.Add(sum, k.NewZero().Div(k.NewOne(), k.NewZero().SetRat(new(big.Rat).SetInt64(n))))
+		exact.Add(exact, r)
+	}
+	lo, hi := sum.Bounds()
+	if e := new(big.Float).SetPrec(256).SetRat(exact); lo.Cmp(e) > 0 || hi.Cmp(e) < 0 {
+		t.Errorf("%v does not contain %v", sum, e)
+	}
+	if lo.Cmp(hi) == 0 {
+		t.Errorf("%v: expected rounding errors", sum)
+	}
+}
+
+func TestIntervalBuchberger(t *testing.T) {
+	tests := []struct {
+		ideal     []string
+		basis     []string
+		ambiguous bool
+	}{
+		{
+			ideal:     []string{"ab-8ba", "a^2-b"},
+			basis:     []string{"a^2-b", "ab", "ba", "b^2"},
+			ambiguous: false,
+		},
+		{
+			// The difference of the two is (3*(1/3)-1)b, whose coefficient only vanishes within the rounding errors of 1/3.
+			ideal:     []string{"a^2-1/3b", "3a^2-b"},
+			basis:     []string{"a^2+[-0.3333333333,-0.3333333333]b", "ba+[-1,-1]ab"},
+			ambiguous: true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"a": 1, "b": 2}
+			k := NewInterval(64)
+			ideal := make([]*nag.Polynomial[*Interval], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = parseInterval(k, variables, s)
+			}
+
+			basis, complete := nag.Buchberger(ideal, 50)
+			if !complete {
+				t.Fatalf("incomplete")
+			}
+			got := make([]string, len(basis))
+			for j, b := range basis {
+				got[j] = b.String()
+			}
+			if !slices.Equal(got, test.basis) {
+				t.Errorf("got %v want %v", got, test.basis)
+			}
+			if k.Ambiguous() != test.ambiguous {
+				t.Errorf("ambiguous: got %v want %v", k.Ambiguous(), test.ambiguous)
+			}
+		})
+	}
+}
+
+// parseFloat parses input over the rationals, and rounds its coefficients to the precision of k.
+func parseFloat(k *Float, variables map[string]nag.Symbol, input string) *nag.Polynomial[*Float] {
+	rp := parseRat(variables, input)
+	p := nag.NewPolynomial(k.NewZero(), rp.Order())
+	p.SymbolStringer = rp.SymbolStringer
+	for c, w := range rp.Terms() {
+		fc := k.NewZero()
+		fc.SetRat(c.Rat)
+		p.Add(p, nag.NewPolynomial(k.NewZero(), rp.Order(), nag.PolynomialTerm[*Float]{Coefficient: fc, Monomial: w}))
+	}
+	return p
+}
+
+// parseInterval parses input over the rationals, and encloses its coefficients in intervals of k.
+func parseInterval(k *Interval, variables map[string]nag.Symbol, input string) *nag.Polynomial[*Interval] {
+	rp := parseRat(variables, input)
+	p := nag.NewPolynomial(k.NewZero(), rp.Order())
+	p.SymbolStringer = rp.SymbolStringer
+	for c, w := range rp.Terms() {
+		ic := k.NewZero().SetRat(c.Rat)
+		p.Add(p, nag.NewPolynomial(k.NewZero(), rp.Order(), nag.PolynomialTerm[*Interval]{Coefficient: ic, Monomial: w}))
+	}
+	return p
+}
@@ -0,0 +1,102 @@
+package field
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/fumin/nag/internal/montgomery"
+)
+
+// An Fp is an element in the prime field GF(p), where p is a prime number less than 2^63.
+// Unlike [PrimeExt], arithmetic is done in machine words without allocation, using [Montgomery multiplication].
+// Elements of the same field share their modulus, which is created once by [NewFp].
+//
+// Montgomery, Peter L. "Modular multiplication without trial division." Mathematics of computation 44.170 (1985): 519-521.
+//
+// [Montgomery multiplication]: https://en.wikipedia.org/wiki/Montgomery_modular_multiplication
+type Fp struct {
+	m *montgomery.Modulus
+	// v is the Montgomery form x*R mod p of the element x, where R = 2^64.
+	v uint64
+}
+
+// NewFp returns the additive identity 0 in the prime field GF(p).
+// NewFp panics if p is not a prime number less than 2^63.
+func NewFp(p uint64) *Fp {
+	return &Fp{m: montgomery.New(p)}
+}
+
+// NewZero returns the additive identity 0.
+func (x *Fp) NewZero() *Fp { return &Fp{m: x.m} }
+
+// NewOne returns the multiplicative identity 1.
+func (x *Fp) NewOne() *Fp { return &Fp{m: x.m, v: x.m.One()} }
+
+// SetUint64 sets z to v mod p and returns z.
+func (z *Fp) SetUint64(v uint64) *Fp {
+	z.v = z.m.ToMont(v)
+	return z
+}
+
+// Uint64 returns the integer representation of x, which is in the range [0, p).
+func (x *Fp) Uint64() uint64 { return x.m.FromMont(x.v) }
+
+// Equal reports whether x and y are equal.
+func (x *Fp) Equal(y *Fp) bool {
+	return x.m.P() == y.m.P() && x.v == y.v
+}
+
+// Add sets z to the sum x+y and returns z.
+func (z *Fp) Add(x, y *Fp) *Fp {
+	z.m = x.m
+	z.v = z.m.Add(x.v, y.v)
+	return z
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *Fp) Sub(x, y *Fp) *Fp {
+	z.m = x.m
+	z.v = z.m.Sub(x.v, y.v)
+	return z
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *Fp) Mul(x, y *Fp) *Fp {
+	z.m = x.m
+	z.v = z.m.Mul(x.v, y.v)
+	return z
+}
+
+// Div sets z to the quotient x/y and returns z.
+func (z *Fp) Div(x, y *Fp) *Fp {
+	xv := x.v
+	z.Inv(y)
+	z.v = z.m.Mul(xv, z.v)
+	return z
+}
+
+// Inv sets z to 1/x and returns z.
+// The inverse is computed by Fermat's little theorem, x^(p-2) = 1/x.
+func (z *Fp) Inv(x *Fp) *Fp {
+	z.m, z.v = x.m, x.m.Inv(x.v)
+	return z
+}
+
+// String returns the integer representation of x.
+func (x *Fp) String() string {
+	return strconv.FormatUint(x.Uint64(), 10)
+}
+
+// Characteristic returns p in the prime field GF(p).
+func (x *Fp) Characteristic() *big.Int {
+	return new(big.Int).SetUint64(x.m.P())
+}
+
+// PrimePower returns 1, since GF(p) is a prime field.
+func (x *Fp) PrimePower() *big.Int { return big.NewInt(1) }
+
+// Ith returns the element i mod p.
+func (x *Fp) Ith(i *big.Int) *Fp {
+	v := new(big.Int).Mod(i, x.Characteristic())
+	return x.NewZero().SetUint64(v.Uint64())
+}
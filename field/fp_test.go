@@ -0,0 +1,151 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFp(t *testing.T) {
+	tests := []struct {
+		p uint64
+	}{
+		{p: 2},
+		{p: 3},
+		{p: 13},
+		{p: 2147483647},
+		{p: 2305843009213693951},
+		{p: 9223372036854775783},
+	}
+
+	for testI, test := range tests {
+		t.Run(fmt.Sprintf("%d", testI), func(t *testing.T) {
+			t.Parallel()
+			k := NewFp(test.p)
+			bigP := new(big.Int).SetUint64(test.p)
+			// want returns the integer representation of op(x, y) computed with big.Int.
+			want := func(op string, x, y uint64) uint64 {
+				bx, by := new(big.Int).SetUint64(x), new(big.Int).SetUint64(y)
+				z := new(big.Int)
+				switch op {
+				case "Add":
+					z.Add(bx, by)
+				case "Sub":
+					z.Sub(bx, by)
+				case "Mul":
+					z.Mul(bx, by)
+				case "Div":
+					z.Mul(bx, new(big.Int).ModInverse(by, bigP))
+				}
+				return z.Mod(z, bigP).Uint64()
+			}
+
+			values := []uint64{0, 1, test.p - 1, test.p / 2}
+			r := rand.New(rand.NewPCG(uint64(testI), test.p))
+			for range 64 {
+				values = append(values, r.Uint64N(test.p))
+			}
+			for _, xv := range values {
+				for _, yv := range values {
+					x, y := k.NewZero().SetUint64(xv), k.NewZero().SetUint64(yv)
+					if z := k.NewZero().Add(x, y); z.Uint64() != want("Add", xv, yv) {
+						t.Errorf("Add(%d %d): got %v want %d", xv, yv, z, want("Add", xv, yv))
+					}
+					if z := k.NewZero().Sub(x, y); z.Uint64() != want("Sub", xv, yv) {
+						t.Errorf("Sub(%d %d): got %v want %d", xv, yv, z, want("Sub", xv, yv))
+					}
+					if z := k.NewZero().Mul(x, y); z.Uint64() != want("Mul", xv, yv) {
+						t.Errorf("Mul(%d %d): got %v want %d", xv, yv, z, want("Mul", xv, yv))
+					}
+					if yv != 0 {
+						if z := k.NewZero().Div(x, y); z.Uint64() != want("Div", xv, yv) {
+							t.Errorf("Div(%d %d): got %v want %d", xv, yv, z, want("Div", xv, yv))
+						}
+						if z := k.NewZero().Inv(y); z.Uint64() != want("Div", 1, yv) {
+							t.Errorf("Inv(%d): got %v want %d", yv, z, want("Div", 1, yv))
+						}
+					}
+				}
+			}
+
+			if one := k.NewOne(); one.Uint64() != 1 || !one.Equal(k.Ith(big.NewInt(1))) {
+				t.Errorf("NewOne: got %v", one)
+			}
+			if x := k.Ith(big.NewInt(-1)); x.Uint64() != test.p-1 {
+				t.Errorf("Ith(-1): got %v want %d", x, test.p-1)
+			}
+			if c := k.Characteristic(); c.Cmp(bigP) != 0 {
+				t.Errorf("Characteristic: got %v want %d", c, test.p)
+			}
+		})
+	}
+}
+
+func TestFpEqual(t *testing.T) {
+	tests := []struct {
+		x  *Fp
+		y  *Fp
+		eq bool
+	}{
+		{
+			x:  NewFp(5).SetUint64(2),
+			y:  NewFp(5).SetUint64(3),
+			eq: false,
+		},
+		{
+			x:  NewFp(11).SetUint64(5),
+			y:  NewFp(7).SetUint64(5),
+			eq: false,
+		},
+		{
+			x:  NewFp(5).SetUint64(3),
+			y:  NewFp(5).SetUint64(8),
+			eq: true,
+		},
+	}
+
+	for testI, test := range tests {
+		t.Run(fmt.Sprintf("%d", testI), func(t *testing.T) {
+			t.Parallel()
+			if eq := test.x.Equal(test.y); eq != test.eq {
+				t.Errorf("%v.Equal(%v): got %v want %v", test.x, test.y, eq, test.eq)
+			}
+		})
+	}
+}
+
+func TestFpFactor(t *testing.T) {
+	tests := []struct {
+		order *big.Int
+		a     string
+	}{
+		{order: big.NewInt(13), a: "x^4+3x^3+2x^2+7x+1"},
+		{order: big.NewInt(2), a: "x^5+x^4+x+1"},
+		{order: big.NewInt(2147483647), a: "x^3-6x^2+11x-6"},
+	}
+
+	for testI, test := range tests {
+		t.Run(fmt.Sprintf("%d", testI), func(t *testing.T) {
+			t.Parallel()
+			a := parseMust(NewFp(test.order.Uint64()), test.a)
+			factors := Factor(a)
+
+			// Check that the factors over Fp are the same as those over prime.
+			tFactors := Factor(parseMust(primeField(test.order), test.a))
+			if len(factors) != len(tFactors) {
+				t.Fatalf("%v: got %v want %v", a, factors, tFactors)
+			}
+			got, want := make(map[string]string), make(map[string]string)
+			for i := range factors {
+				got[factors[i].P.String()] = factors[i].N.String()
+				want[tFactors[i].P.String()] = tFactors[i].N.String()
+			}
+			for p, n := range want {
+				if got[p] != n {
+					t.Errorf("%v: got %v want %v", a, factors, tFactors)
+				}
+			}
+		})
+	}
+}
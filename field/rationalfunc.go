@@ -0,0 +1,463 @@
+package field
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fumin/nag"
+)
+
+// A RationalFunc is an element in the field of rational functions K(t1,...,tk), whose parameters t1,...,tk commute with each other and with all symbols.
+// Symbolic parameters in an ideal, such as λ in (1-λ)L+λR, can thus be kept in the coefficients instead of being declared as commuting variables, and [nag.Buchberger] computes a basis that is generic in the parameters.
+// Note that such a basis may not specialize to the Gröbner basis for particular values of the parameters, such as those that vanish the denominators of the basis.
+//
+// A RationalFunc is stored as a fraction num/den of polynomials in the parameters, where the greatest common divisor of num and den is canceled, and den is monic.
+// The greatest common divisor of multivariate polynomials is computed recursively by the primitive polynomial remainder sequence, see Knuth.
+//
+// Knuth, Donald E. "The Art of Computer Programming, Volume 2: Seminumerical Algorithms." Section 4.6.1, Addison-Wesley, 1997.
+type RationalFunc[K nag.Field[K]] struct {
+	f   *rationalFuncField[K]
+	num cpoly[K]
+	den cpoly[K]
+}
+
+// A rationalFuncField holds the parameters of a field of rational functions, which are shared by all its elements.
+type rationalFuncField[K nag.Field[K]] struct {
+	k      K
+	params []string
+
+	// zero is the Gröbner basis of the polynomials in the parameters that are assumed to vanish, see [ComprehensiveBuchberger].
+	// Numerators are reduced modulo zero, so that coefficients vanishing under the assumption are zero.
+	zero []*nag.Polynomial[K]
+	// reducer divides by zero, and is built once for all elements of the field.
+	reducer *nag.Reducer[K]
+	// divisors records the numerators of the divisors in Div and Inv if it is not nil.
+	divisors *divisorSet[K]
+}
+
+// A divisorSet is a set of monic polynomials in the parameters, which is safe for concurrent use.
+type divisorSet[K nag.Field[K]] struct {
+	sync.Mutex
+	p []cpoly[K]
+}
+
+// add adds the monic polynomial of p to s, unless p is a constant.
+func (s *divisorSet[K]) add(p cpoly[K]) {
+	if p.isConstant() {
+		return
+	}
+	p = p.monic()
+	s.Lock()
+	defer s.Unlock()
+	if !slices.ContainsFunc(s.p, p.equal) {
+		s.p = append(s.p, p)
+	}
+}
+
+// NewRationalFunc returns the additive identity 0 in the field of rational functions over k, with parameters named params.
+func NewRationalFunc[K nag.Field[K]](k K, params ...string) *RationalFunc[K] {
+	f := &rationalFuncField[K]{k: k.NewZero(), params: slices.Clone(params)}
+	return &RationalFunc[K]{f: f, den: f.one()}
+}
+
+// Param returns the i'th parameter of the field of x.
+func (x *RationalFunc[K]) Param(i int) *RationalFunc[K] {
+	if i < 0 || i >= len(x.f.params) {
+		panic(fmt.Sprintf("parameter %d out of range %d", i, len(x.f.params)))
+	}
+	e := make([]int, len(x.f.params))
+	e[i] = 1
+	return &RationalFunc[K]{f: x.f, num: cpoly[K]{{c: x.f.k.NewOne(), e: e}}, den: x.f.one()}
+}
+
+// SetScalar sets z to the constant c and returns z.
+func (z *RationalFunc[K]) SetScalar(c K) *RationalFunc[K] {
+	z.num, z.den = nil, z.f.one()
+	if !c.Equal(c.NewZero()) {
+		z.num = cpoly[K]{{c: c.NewZero().Add(c.NewZero(), c), e: make([]int, len(z.f.params))}}
+	}
+	return z
+}
+
+// Parametrize returns p as a polynomial over the field of x, where each symbol params[i] in p is replaced by the i'th parameter of the field.
+// Since parameters commute with all symbols, their positions in the monomials of p do not matter.
+func (x *RationalFunc[K]) Parametrize(p *nag.Polynomial[K], params ...nag.Symbol) *nag.Polynomial[*RationalFunc[K]] {
+	z := nag.NewPolynomial(x.NewZero(), p.Order())
+	z.SymbolStringer = p.SymbolStringer
+	for c, w := range p.Terms() {
+		e := make([]int, len(x.f.params))
+		var rest nag.Monomial
+		for _, s := range w {
+			if i := slices.Index(params, s); i != -1 {
+				e[i]++
+			} else {
+				rest = append(rest, s)
+			}
+		}
+		rc := &RationalFunc[K]{f: x.f, num: cpoly[K]{{c: c.NewZero().Add(c.NewZero(), c), e: e}}, den: x.f.one()}
+		z.Add(z, nag.NewPolynomial(x.NewZero(), p.Order(), nag.PolynomialTerm[*RationalFunc[K]]{Coefficient: rc, Monomial: rest}))
+	}
+	return z
+}
+
+// NewZero returns the additive identity 0.
+func (x *RationalFunc[K]) NewZero() *RationalFunc[K] {
+	return &RationalFunc[K]{f: x.f, den: x.f.one()}
+}
+
+// NewOne returns the multiplicative identity 1.
+func (x *RationalFunc[K]) NewOne() *RationalFunc[K] {
+	return &RationalFunc[K]{f: x.f, num: x.f.one(), den: x.f.one()}
+}
+
+// Equal reports whether x and y are equal.
+func (x *RationalFunc[K]) Equal(y *RationalFunc[K]) bool {
+	if x.f != y.f && !slices.Equal(x.f.params, y.f.params) {
+		return false
+	}
+	if x.f.zero == nil {
+		return x.num.equal(y.num) && x.den.equal(y.den)
+	}
+	// Fractions are not unique modulo the vanishing polynomials.
+	return len(x.f.reduce(x.num.mul(y.den).add(y.num.mul(x.den), -1))) == 0
+}
+
+// Add sets z to the sum x+y and returns z.
+func (z *RationalFunc[K]) Add(x, y *RationalFunc[K]) *RationalFunc[K] {
+	return z.set(x.f, x.num.mul(y.den).add(y.num.mul(x.den), 1), x.den.mul(y.den))
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *RationalFunc[K]) Sub(x, y *RationalFunc[K]) *RationalFunc[K] {
+	return z.set(x.f, x.num.mul(y.den).add(y.num.mul(x.den), -1), x.den.mul(y.den))
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *RationalFunc[K]) Mul(x, y *RationalFunc[K]) *RationalFunc[K] {
+	return z.set(x.f, x.num.mul(y.num), x.den.mul(y.den))
+}
+
+// Div sets z to the quotient x/y and returns z.
+func (z *RationalFunc[K]) Div(x, y *RationalFunc[K]) *RationalFunc[K] {
+	if len(y.num) == 0 {
+		panic("division by zero")
+	}
+	if y.f.divisors != nil {
+		y.f.divisors.add(y.num)
+	}
+	return z.set(x.f, x.num.mul(y.den), x.den.mul(y.num))
+}
+
+// Inv sets z to 1/x and returns z.
+func (z *RationalFunc[K]) Inv(x *RationalFunc[K]) *RationalFunc[K] {
+	if len(x.num) == 0 {
+		panic("division by zero")
+	}
+	if x.f.divisors != nil {
+		x.f.divisors.add(x.num)
+	}
+	return z.set(x.f, x.den, x.num)
+}
+
+// String returns the fraction num/den representing x.
+// Nonconstant elements are enclosed in parentheses, so that they print unambiguously as coefficients of polynomials.
+func (x *RationalFunc[K]) String() string {
+	num := x.f.polynomial(x.num).String()
+	if x.den.isOne() {
+		if len(x.num) <= 1 && x.num.isConstant() {
+			return num
+		}
+		return "(" + num + ")"
+	}
+	return "(" + parenthesize(num, len(x.num)) + "/" + parenthesize(x.f.polynomial(x.den).String(), len(x.den)) + ")"
+}
+
+// parenthesize encloses the string s of a polynomial with n terms in parentheses, if it is a sum or contains a fraction.
+func parenthesize(s string, n int) string {
+	if n > 1 || strings.Contains(s, "/") {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// set sets z to num/den in the field f, after canceling their greatest common divisor and making den monic.
+// The numerator is reduced modulo the vanishing polynomials of f.
+func (z *RationalFunc[K]) set(f *rationalFuncField[K], num, den cpoly[K]) *RationalFunc[K] {
+	z.f = f
+	num = f.reduce(num)
+	if len(num) == 0 {
+		z.num, z.den = nil, f.one()
+		return z
+	}
+	g := gcdCommutative(num, den, 0)
+	num, den = num.divideExact(g), den.divideExact(g)
+	lc := den[0].c
+	z.num, z.den = num.scale(lc.NewZero().Inv(lc)), den.monic()
+	return z
+}
+
+// reduce returns the remainder of p modulo the vanishing polynomials of f.
+func (f *rationalFuncField[K]) reduce(p cpoly[K]) cpoly[K] {
+	if f.zero == nil || len(p) == 0 {
+		return p
+	}
+	return f.cpoly(f.reducer.NormalForm(f.polynomial(p)))
+}
+
+// cpoly is the inverse of polynomial.
+func (f *rationalFuncField[K]) cpoly(p *nag.Polynomial[K]) cpoly[K] {
+	k := len(f.params)
+	var z cpoly[K]
+	for c, w := range p.Terms() {
+		e := make([]int, k)
+		for _, s := range w {
+			e[k-1-int(s)]++
+		}
+		z = z.add(cpoly[K]{{c: c, e: e}}, 1)
+	}
+	return z
+}
+
+func (f *rationalFuncField[K]) one() cpoly[K] {
+	return cpoly[K]{{c: f.k.NewOne(), e: make([]int, len(f.params))}}
+}
+
+// polynomial returns p as a polynomial whose symbols are printed as the names of the parameters.
+// The i'th parameter is the symbol k-1-i, so that earlier parameters are larger in [nag.Deglex] and printed first.
+func (f *rationalFuncField[K]) polynomial(p cpoly[K]) *nag.Polynomial[K] {
+	k := len(f.params)
+	z := nag.NewPolynomial(f.k.NewZero(), nag.Deglex)
+	z.SymbolStringer = func(s nag.Symbol) string { return f.params[k-1-int(s)] }
+	for _, t := range p {
+		var w nag.Monomial
+		for i, n := range t.e {
+			for range n {
+				w = append(w, nag.Symbol(k-1-i))
+			}
+		}
+		z.Add(z, nag.NewPolynomial(f.k.NewZero(), nag.Deglex, nag.PolynomialTerm[K]{Coefficient: t.c, Monomial: w}))
+	}
+	return z
+}
+
+// A cpoly is a polynomial in commuting variables, whose terms have nonzero coefficients and are sorted in decreasing lexicographic order of their exponents.
+// A cpoly is never modified after creation, so that it can be shared among elements.
+type cpoly[K nag.Field[K]] []cterm[K]
+
+// A cterm is the term c*t1^e[0]*...*tk^e[k-1].
+type cterm[K nag.Field[K]] struct {
+	c K
+	e []int
+}
+
+func lexCmp(x, y []int) int {
+	for i := range x {
+		if c := cmp.Compare(x[i], y[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func (p cpoly[K]) equal(q cpoly[K]) bool {
+	return slices.EqualFunc(p, q, func(x, y cterm[K]) bool { return x.c.Equal(y.c) && slices.Equal(x.e, y.e) })
+}
+
+func (p cpoly[K]) isConstant() bool {
+	for _, t := range p {
+		if slices.ContainsFunc(t.e, func(n int) bool { return n != 0 }) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p cpoly[K]) isOne() bool {
+	return len(p) == 1 && p.isConstant() && p[0].c.Equal(p[0].c.NewOne())
+}
+
+// scale returns c*p.
+func (p cpoly[K]) scale(c K) cpoly[K] {
+	z := make(cpoly[K], 0, len(p))
+	for _, t := range p {
+		tc := c.NewZero().Mul(c, t.c)
+		if !tc.Equal(c.NewZero()) {
+			z = append(z, cterm[K]{c: tc, e: t.e})
+		}
+	}
+	return z
+}
+
+// monic returns p divided by its leading coefficient.
+func (p cpoly[K]) monic() cpoly[K] {
+	if len(p) == 0 {
+		return p
+	}
+	lc := p[0].c
+	return p.scale(lc.NewZero().Inv(lc))
+}
+
+// add returns x + sign*y.
+func (x cpoly[K]) add(y cpoly[K], sign int) cpoly[K] {
+	z := make(cpoly[K], 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		var c int
+		switch {
+		case i == len(x):
+			c = -1
+		case j == len(y):
+			c = 1
+		default:
+			c = lexCmp(x[i].e, y[j].e)
+		}
+		switch {
+		case c > 0:
+			z = append(z, x[i])
+			i++
+		case c < 0:
+			yc := y[j].c.NewZero()
+			if sign < 0 {
+				yc = yc.Sub(yc, y[j].c)
+			} else {
+				yc = yc.Add(yc, y[j].c)
+			}
+			z = append(z, cterm[K]{c: yc, e: y[j].e})
+			j++
+		default:
+			zc := x[i].c.NewZero()
+			if sign < 0 {
+				zc = zc.Sub(x[i].c, y[j].c)
+			} else {
+				zc = zc.Add(x[i].c, y[j].c)
+			}
+			if !zc.Equal(zc.NewZero()) {
+				z = append(z, cterm[K]{c: zc, e: x[i].e})
+			}
+			i++
+			j++
+		}
+	}
+	return z
+}
+
+// mulTerm returns c*t^e*x.
+// Since the lexicographic order is a monomial order, the terms remain sorted.
+func (x cpoly[K]) mulTerm(c K, e []int) cpoly[K] {
+	z := make(cpoly[K], 0, len(x))
+	for _, t := range x {
+		te := make([]int, len(e))
+		for i := range e {
+			te[i] = t.e[i] + e[i]
+		}
+		z = append(z, cterm[K]{c: c.NewZero().Mul(c, t.c), e: te})
+	}
+	return z
+}
+
+func (x cpoly[K]) mul(y cpoly[K]) cpoly[K] {
+	var z cpoly[K]
+	for _, t := range y {
+		z = z.add(x.mulTerm(t.c, t.e), 1)
+	}
+	return z
+}
+
+// divideExact returns x/y, where y is known to divide x.
+func (x cpoly[K]) divideExact(y cpoly[K]) cpoly[K] {
+	var q cpoly[K]
+	lt := y[0]
+	for len(x) != 0 {
+		e := make([]int, len(lt.e))
+		for i := range e {
+			e[i] = x[0].e[i] - lt.e[i]
+			if e[i] < 0 {
+				panic(fmt.Sprintf("%v does not divide %v", y, x))
+			}
+		}
+		c := lt.c.NewZero().Div(x[0].c, lt.c)
+		q = q.add(cpoly[K]{{c: c, e: e}}, 1)
+		x = x.add(y.mulTerm(c, e), -1)
+	}
+	return q
+}
+
+// degree returns the degree of p in the v'th variable.
+func (p cpoly[K]) degree(v int) int {
+	d := -1
+	for _, t := range p {
+		d = max(d, t.e[v])
+	}
+	return d
+}
+
+// coefficients returns the coefficients of p as a polynomial in the v'th variable, keyed by the degree in the v'th variable.
+func (p cpoly[K]) coefficients(v int) map[int]cpoly[K] {
+	coeffs := make(map[int]cpoly[K])
+	for _, t := range p {
+		e := slices.Clone(t.e)
+		e[v] = 0
+		coeffs[t.e[v]] = append(coeffs[t.e[v]], cterm[K]{c: t.c, e: e})
+	}
+	// The terms of each coefficient may be out of order if the v'th variable is not the most significant.
+	for d, c := range coeffs {
+		slices.SortFunc(c, func(x, y cterm[K]) int { return lexCmp(y.e, x.e) })
+		coeffs[d] = c
+	}
+	return coeffs
+}
+
+// content returns the greatest common divisor of the coefficients of p as a polynomial in the v'th variable.
+func (p cpoly[K]) content(v int) cpoly[K] {
+	var g cpoly[K]
+	for _, c := range p.coefficients(v) {
+		g = gcdCommutative(g, c, v+1)
+	}
+	return g
+}
+
+// prem returns the pseudo-remainder of x divided by y, as polynomials in the v'th variable.
+func (x cpoly[K]) prem(y cpoly[K], v int) cpoly[K] {
+	dy := y.degree(v)
+	lcy := y.coefficients(v)[dy]
+	for len(x) != 0 && x.degree(v) >= dy {
+		dx := x.degree(v)
+		lcx := x.coefficients(v)[dx]
+		e := make([]int, len(y[0].e))
+		e[v] = dx - dy
+		x = x.mul(lcy).add(y.mulTerm(lcy[0].c.NewOne(), e).mul(lcx), -1)
+	}
+	return x
+}
+
+// gcdCommutative returns the monic greatest common divisor of x and y, whose variables before the v'th do not appear.
+// It computes the primitive polynomial remainder sequence in the v'th variable, and recurses on the contents in the remaining variables.
+func gcdCommutative[K nag.Field[K]](x, y cpoly[K], v int) cpoly[K] {
+	switch {
+	case len(x) == 0:
+		return y.monic()
+	case len(y) == 0:
+		return x.monic()
+	case v == len(x[0].e):
+		return cpoly[K]{{c: x[0].c.NewOne(), e: make([]int, v)}}
+	case x.degree(v) <= 0 && y.degree(v) <= 0:
+		return gcdCommutative(x, y, v+1)
+	}
+
+	cx, cy := x.content(v), y.content(v)
+	x, y = x.divideExact(cx), y.divideExact(cy)
+	if x.degree(v) < y.degree(v) {
+		x, y = y, x
+	}
+	for len(y) != 0 {
+		r := x.prem(y, v)
+		if len(r) != 0 {
+			r = r.divideExact(r.content(v))
+		}
+		x, y = y, r
+	}
+	return x.mul(gcdCommutative(cx, cy, v+1)).monic()
+}
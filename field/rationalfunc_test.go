@@ -0,0 +1,148 @@
+package field
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/fumin/nag"
+)
+
+func TestRationalFunc(t *testing.T) {
+	tests := []struct {
+		x string
+		y string
+		// sum, diff, prod and quo are x+y, x-y, x*y and x/y.
+		sum  string
+		diff string
+		prod string
+		quo  string
+	}{
+		{x: "t", y: "1", sum: "(t+1)", diff: "(t-1)", prod: "(t)", quo: "(t)"},
+		{x: "t^2-1", y: "t-1", sum: "(t^2+t-2)", diff: "(t^2-t)", prod: "(t^3-t^2-t+1)", quo: "(t+1)"},
+		{x: "t^2-s^2", y: "t^2+2ts+s^2", sum: "(2t^2+2ts)", diff: "(-2ts-2s^2)", prod: "(t^4+2t^3s-2ts^3-s^4)", quo: "((t-s)/(t+s))"},
+		{x: "2t", y: "4ts-2t", sum: "(4ts)", diff: "(-4ts+4t)", prod: "(8t^2s-4t^2)", quo: "((1/2)/(s-1/2))"},
+		{x: "ts-s+t-1", y: "s^2+2s+1", sum: "(ts+s^2+t+s)", diff: "(ts-s^2+t-3s-2)", prod: "(ts^3+3ts^2-s^3+3ts-3s^2+t-3s-1)", quo: "((t-1)/(s+1))"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"t": 0, "s": 1}
+			k := NewRationalFunc(nag.NewRat(0, 1), "t", "s")
+			parse := func(s string) *RationalFunc[*nag.Rat] {
+				p := k.Parametrize(parseRat(variables, s), 0, 1)
+				if p.Len() == 0 {
+					return k.NewZero()
+				}
+				return p.LeadingTerm().Coefficient
+			}
+			x, y := parse(test.x), parse(test.y)
+			if z := k.NewZero().Add(x, y); z.String() != test.sum {
+				t.Errorf("Add(%v %v): got %v want %v", x, y, z, test.sum)
+			}
+			if z := k.NewZero().Sub(x, y); z.String() != test.diff {
+				t.Errorf("Sub(%v %v): got %v want %v", x, y, z, test.diff)
+			}
+			if z := k.NewZero().Mul(x, y); z.String() != test.prod {
+				t.Errorf("Mul(%v %v): got %v want %v", x, y, z, test.prod)
+			}
+			if z := k.NewZero().Div(x, y); z.String() != test.quo {
+				t.Errorf("Div(%v %v): got %v want %v", x, y, z, test.quo)
+			}
+			if z := k.NewZero().Mul(y, k.NewZero().Inv(y)); !z.Equal(k.NewOne()) {
+				t.Errorf("Mul(%v, Inv(%v)): got %v want 1", y, y, z)
+			}
+			// (x/y)*y == x checks that fractions are canonical.
+			if z := k.NewZero().Mul(k.NewZero().Div(x, y), y); !z.Equal(x) {
+				t.Errorf("Mul(Div(%v, %v), %v): got %v want %v", x, y, y, z, x)
+			}
+		})
+	}
+}
+
+func TestGCDCommutative(t *testing.T) {
+	tests := []struct {
+		x   string
+		y   string
+		gcd string
+	}{
+		{x: "0", y: "2t+2", gcd: "t+1"},
+		{x: "3", y: "t", gcd: "1"},
+		{x: "t^2-1", y: "t^2+2t+1", gcd: "t+1"},
+		{x: "(t+s)(t-s)(u+1)", y: "(t+s)^2(u+1)(u-1)", gcd: "tu+su+t+s"},
+		{x: "(ts+u)(t-1)", y: "(ts+u)(s-1)", gcd: "ts+u"},
+		{x: "(t^2+s^2+u^2)(tsu-1)^2", y: "(tsu-1)(t+s+u)", gcd: "tsu-1"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"t": 0, "s": 1, "u": 2}
+			k := NewRationalFunc(nag.NewRat(0, 1), "t", "s", "u")
+			parse := func(s string) cpoly[*nag.Rat] {
+				p := k.Parametrize(parseRat(variables, s), 0, 1, 2)
+				if p.Len() == 0 {
+					return nil
+				}
+				return p.LeadingTerm().Coefficient.num
+			}
+			x, y := parse(test.x), parse(test.y)
+			if g := k.f.polynomial(gcdCommutative(x, y, 0)); g.String() != test.gcd {
+				t.Errorf("gcd(%v, %v): got %v want %v", test.x, test.y, g, test.gcd)
+			}
+		})
+	}
+}
+
+func TestRationalFuncBuchberger(t *testing.T) {
+	tests := []struct {
+		ideal []string
+		basis []string
+	}{
+		{
+			// Since ab = a^3 = ba, the deformation ab = λba implies ab = 0 for generic λ.
+			ideal: []string{"ab-λba", "a^2-b"},
+			basis: []string{"a^2-b", "ab", "ba", "b^2"},
+		},
+		{
+			// Since a^3 = λab = λba, the deformation (1-λ)ab+λba = μ reduces to ab = ba = μ.
+			ideal: []string{"a^2-λb", "(1-λ)ab+λba-μ"},
+			basis: []string{"a^2+(-λ)b", "ab+(-μ)", "ba+(-μ)", "b^2+(-μ/λ)a"},
+		},
+		{
+			// Since a = b^2a = λ^2ab^2 = λ^2a, a vanishes unless λ = ±1.
+			ideal: []string{"ba-λab", "b^2-1"},
+			basis: []string{"a", "b^2-1"},
+		},
+		{
+			// Since b = λa^2 = λμb, b vanishes unless λμ = 1.
+			ideal: []string{"λa^2-b", "a^2-μb"},
+			basis: []string{"b", "a^2"},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			variables := map[string]nag.Symbol{"λ": 0, "μ": 3, "a": 1, "b": 2}
+			k := NewRationalFunc(nag.NewRat(0, 1), "λ", "μ")
+			ideal := make([]*nag.Polynomial[*RationalFunc[*nag.Rat]], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = k.Parametrize(parseRat(variables, s), 0, 3)
+			}
+
+			basis, complete := nag.Buchberger(ideal, 50)
+			if !complete {
+				t.Fatalf("incomplete")
+			}
+			got := make([]string, len(basis))
+			for j, b := range basis {
+				got[j] = b.String()
+			}
+			if !slices.Equal(got, test.basis) {
+				t.Errorf("got %v want %v", got, test.basis)
+			}
+		})
+	}
+}
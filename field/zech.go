@@ -0,0 +1,257 @@
+package field
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// zechMaxOrder is the largest order of a finite field for which [NewZechExt] precomputes tables.
+const zechMaxOrder = 1 << 16
+
+// A ZechExt is an element in the finite field GF(p^n), which is an alternative to [PrimeExt] for small fields.
+// Every nonzero element is represented by its discrete logarithm to the base x, where x is the root of the irreducible polynomial.
+// Multiplication and division are thus additions and subtractions of logarithms, whereas addition uses a precomputed table of [Zech logarithms]:
+//
+//	x^a + x^b = x^(a + Z(b-a)), where x^Z(n) = 1 + x^n
+//
+// Tables are precomputed only if the field has at most 2^16 elements, and x generates the multiplicative group of the field.
+// Otherwise, a ZechExt falls back to the polynomial arithmetic of [PrimeExt].
+// For more details, please see Huber.
+//
+// Huber, Klaus. "Some comments on Zech's logarithms." IEEE Transactions on Information Theory 36.4 (1990): 946-950.
+//
+// [Zech logarithms]: https://en.wikipedia.org/wiki/Zech%27s_logarithm
+type ZechExt struct {
+	t *zechTable
+	// e is the discrete logarithm of the element, or -1 for zero.
+	// It is only used if the field is tabulated.
+	e int
+	// ext is the element in polynomial form, which is only used if the field is not tabulated.
+	ext *PrimeExt
+}
+
+// A zechTable holds the precomputed tables of a finite field, which are shared by all its elements.
+type zechTable struct {
+	irr *IrreduciblePoly
+	// q is the order of the field.
+	q int
+	// log maps the integer representation of an element to its discrete logarithm, with log[0] = -1.
+	// A nil log means the field is not tabulated.
+	log []int32
+	// antilog maps a discrete logarithm to the integer representation of its element.
+	antilog []int32
+	// zech maps n to Z(n), where x^Z(n) = 1 + x^n, or -1 if 1 + x^n = 0.
+	zech []int32
+	// negOne is the discrete logarithm of -1.
+	negOne int
+}
+
+// NewZechExt returns the additive identity 0 in the finite field GF(p^n) defined by irr.
+// Elements of the field are obtained by [ZechExt.Ith], and share the tables precomputed by NewZechExt.
+func NewZechExt(irr *IrreduciblePoly) *ZechExt {
+	t := newZechTable(irr)
+	if t.log == nil {
+		return &ZechExt{t: t, ext: irr.Ext(big.NewInt(0))}
+	}
+	return &ZechExt{t: t, e: -1}
+}
+
+func newZechTable(irr *IrreduciblePoly) *zechTable {
+	irrp := irr.Polynomial
+	t := &zechTable{irr: &IrreduciblePoly{poly0(irrp).Set(irrp)}}
+	p := irrp.LeadingTerm().Coefficient.Characteristic()
+	order := new(big.Int).Exp(p, big.NewInt(int64(len(irrp.LeadingTerm().Monomial))), nil)
+	if order.Cmp(big.NewInt(zechMaxOrder)) > 0 {
+		return t
+	}
+	t.q = int(order.Int64())
+
+	// Compute the powers of x, with each element in base p digits.
+	// The irreducible polynomial is monic, so x^n = -sum c_k*x^k for the lower terms c_k*x^k of the polynomial.
+	base, n := int(p.Int64()), len(irrp.LeadingTerm().Monomial)
+	low := make([]int, n)
+	for c, w := range irrp.Terms() {
+		if len(w) < n {
+			low[len(w)] = int(c.i.Int64())
+		}
+	}
+	log := make([]int32, t.q)
+	antilog := make([]int32, t.q-1)
+	for i := range log {
+		log[i] = -1
+	}
+	digits := make([]int, n)
+	digits[0] = 1
+	for e := range t.q - 1 {
+		ith := 0
+		for k := n - 1; k >= 0; k-- {
+			ith = ith*base + digits[k]
+		}
+		// x is not primitive if some power x^e repeats before generating all nonzero elements.
+		if log[ith] != -1 {
+			return t
+		}
+		log[ith], antilog[e] = int32(e), int32(ith)
+
+		// Multiply by x.
+		top := digits[n-1]
+		copy(digits[1:], digits[:n-1])
+		digits[0] = 0
+		for k := range digits {
+			digits[k] = ((digits[k]-top*low[k])%base + base) % base
+		}
+	}
+
+	// Compute the Zech logarithms, where adding one increments the lowest digit.
+	zech := make([]int32, t.q-1)
+	for e := range zech {
+		ith := int(antilog[e])
+		d := ith % base
+		zech[e] = log[ith-d+(d+1)%base]
+	}
+	t.log, t.antilog, t.zech = log, antilog, zech
+	if base != 2 {
+		t.negOne = (t.q - 1) / 2
+	}
+	return t
+}
+
+// NewZero returns the additive identity 0.
+func (x *ZechExt) NewZero() *ZechExt {
+	if x.t.log == nil {
+		return &ZechExt{t: x.t, ext: x.ext.NewZero()}
+	}
+	return &ZechExt{t: x.t, e: -1}
+}
+
+// NewOne returns the multiplicative identity 1.
+func (x *ZechExt) NewOne() *ZechExt {
+	if x.t.log == nil {
+		return &ZechExt{t: x.t, ext: x.ext.NewOne()}
+	}
+	return &ZechExt{t: x.t, e: 0}
+}
+
+// Equal reports whether x and y are equal.
+func (x *ZechExt) Equal(y *ZechExt) bool {
+	if x.t != y.t && !x.t.irr.Equal(y.t.irr.Polynomial) {
+		return false
+	}
+	if x.t.log == nil {
+		return x.ext.Equal(y.ext)
+	}
+	return x.e == y.e
+}
+
+// Add sets z to the sum x+y and returns z.
+func (z *ZechExt) Add(x, y *ZechExt) *ZechExt {
+	z.t = x.t
+	if z.t.log == nil {
+		z.ext = x.ext.NewZero().Add(x.ext, y.ext)
+		return z
+	}
+	z.e = z.t.add(x.e, y.e)
+	return z
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *ZechExt) Sub(x, y *ZechExt) *ZechExt {
+	z.t = x.t
+	if z.t.log == nil {
+		z.ext = x.ext.NewZero().Sub(x.ext, y.ext)
+		return z
+	}
+	z.e = z.t.add(x.e, z.t.mul(y.e, z.t.negOne))
+	return z
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *ZechExt) Mul(x, y *ZechExt) *ZechExt {
+	z.t = x.t
+	if z.t.log == nil {
+		z.ext = x.ext.NewZero().Mul(x.ext, y.ext)
+		return z
+	}
+	z.e = z.t.mul(x.e, y.e)
+	return z
+}
+
+// Div sets z to the quotient x/y and returns z.
+func (z *ZechExt) Div(x, y *ZechExt) *ZechExt {
+	xe, xext := x.e, x.ext
+	z.Inv(y)
+	if z.t.log == nil {
+		z.ext.Mul(xext, z.ext)
+		return z
+	}
+	z.e = z.t.mul(xe, z.e)
+	return z
+}
+
+// Inv sets z to 1/x and returns z.
+func (z *ZechExt) Inv(x *ZechExt) *ZechExt {
+	z.t = x.t
+	if z.t.log == nil {
+		z.ext = x.ext.NewZero().Inv(x.ext)
+		return z
+	}
+	if x.e == -1 {
+		panic("division by zero")
+	}
+	z.e = (z.t.q - 1 - x.e) % (z.t.q - 1)
+	return z
+}
+
+// String returns the integer representation of x.
+func (x *ZechExt) String() string {
+	if x.t.log == nil {
+		return x.ext.String()
+	}
+	if x.e == -1 {
+		return "0"
+	}
+	return strconv.Itoa(int(x.t.antilog[x.e]))
+}
+
+func (x *ZechExt) Characteristic() *big.Int {
+	return x.t.irr.LeadingTerm().Coefficient.Characteristic()
+}
+
+func (x *ZechExt) PrimePower() *big.Int {
+	return big.NewInt(int64(len(x.t.irr.LeadingTerm().Monomial)))
+}
+
+func (x *ZechExt) Ith(i *big.Int) *ZechExt {
+	if x.t.log == nil {
+		return &ZechExt{t: x.t, ext: x.t.irr.Ext(i)}
+	}
+	ith := new(big.Int).Mod(i, big.NewInt(int64(x.t.q)))
+	return &ZechExt{t: x.t, e: int(x.t.log[ith.Int64()])}
+}
+
+// mul returns the discrete logarithm of the product of the elements with logarithms a and b.
+func (t *zechTable) mul(a, b int) int {
+	if a == -1 || b == -1 {
+		return -1
+	}
+	return (a + b) % (t.q - 1)
+}
+
+// add returns the discrete logarithm of the sum of the elements with logarithms a and b.
+func (t *zechTable) add(a, b int) int {
+	if a == -1 {
+		return b
+	}
+	if b == -1 {
+		return a
+	}
+	n := b - a
+	if n < 0 {
+		n += t.q - 1
+	}
+	z := int(t.zech[n])
+	if z == -1 {
+		return -1
+	}
+	return (a + z) % (t.q - 1)
+}
@@ -0,0 +1,61 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestZechExt(t *testing.T) {
+	tests := []struct {
+		irr       *IrreduciblePoly
+		tabulated bool
+	}{
+		{irr: NewIrreduciblePoly(big.NewInt(2), 1), tabulated: true},
+		{irr: NewIrreduciblePoly(big.NewInt(13), 1), tabulated: true},
+		{irr: NewIrreduciblePoly(big.NewInt(2), 4), tabulated: true},
+		{irr: NewIrreduciblePoly(big.NewInt(3), 3), tabulated: true},
+		{irr: NewIrreduciblePoly(big.NewInt(5), 2), tabulated: true},
+		// x is not primitive, since x^5 = 1.
+		{irr: &IrreduciblePoly{parseMust(primeField(big.NewInt(2)), "x^4+x^3+x^2+x+1")}, tabulated: false},
+	}
+
+	for testI, test := range tests {
+		t.Run(fmt.Sprintf("%d", testI), func(t *testing.T) {
+			t.Parallel()
+			k := NewZechExt(test.irr)
+			if tabulated := k.t.log != nil; tabulated != test.tabulated {
+				t.Fatalf("tabulated: got %v want %v", tabulated, test.tabulated)
+			}
+
+			// Check the arithmetics against PrimeExt.
+			order := new(big.Int).Exp(k.Characteristic(), k.PrimePower(), nil)
+			for i := big.NewInt(0); i.Cmp(order) < 0; i.Add(i, big.NewInt(1)) {
+				for j := big.NewInt(0); j.Cmp(order) < 0; j.Add(j, big.NewInt(1)) {
+					x, y := k.Ith(i), k.Ith(j)
+					px, py := test.irr.Ext(i), test.irr.Ext(j)
+					if x.String() != px.String() {
+						t.Fatalf("Ith(%v): got %v want %v", i, x, px)
+					}
+					if z, pz := k.NewZero().Add(x, y), px.NewZero().Add(px, py); z.String() != pz.String() {
+						t.Errorf("Add(%v %v): got %v want %v", x, y, z, pz)
+					}
+					if z, pz := k.NewZero().Sub(x, y), px.NewZero().Sub(px, py); z.String() != pz.String() {
+						t.Errorf("Sub(%v %v): got %v want %v", x, y, z, pz)
+					}
+					if z, pz := k.NewZero().Mul(x, y), px.NewZero().Mul(px, py); z.String() != pz.String() {
+						t.Errorf("Mul(%v %v): got %v want %v", x, y, z, pz)
+					}
+					if j.Sign() != 0 {
+						if z, pz := k.NewZero().Div(x, y), px.NewZero().Div(px, py); z.String() != pz.String() {
+							t.Errorf("Div(%v %v): got %v want %v", x, y, z, pz)
+						}
+					}
+					if eq := x.Equal(y); eq != (i.Cmp(j) == 0) {
+						t.Errorf("%v.Equal(%v): got %v", x, y, eq)
+					}
+				}
+			}
+		})
+	}
+}
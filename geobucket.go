@@ -0,0 +1,166 @@
+package nag
+
+// geobucketBase is the ratio between the capacities of consecutive buckets in a geobucket.
+const geobucketBase = 4
+
+// A term is a term of a sorted term vector, which lists the terms of a polynomial in decreasing order of their monomials.
+type term[K Field[K]] struct {
+	c K
+	w wordID
+}
+
+// termVector returns the terms of x in decreasing order, except for its leading term if tail is true.
+// The coefficients and monomials are shared with x.
+func termVector[K Field[K]](x *Polynomial[K], tail bool) []term[K] {
+	v := make([]term[K], 0, x.m.Len())
+	for w, c := range x.m.Backward() {
+		v = append(v, term[K]{c: c, w: w})
+	}
+	if tail && len(v) > 0 {
+		v = v[1:]
+	}
+	return v
+}
+
+// A geobucket is a polynomial stored as a sum of sorted term vectors, whose lengths grow geometrically.
+// Adding a polynomial of n terms merges it with a bucket of comparable length, so that repeatedly adding short polynomials to a long one does not touch all terms of the long one.
+// This makes geobuckets suitable for the intermediate polynomial of a division, which receives many additions but only ever needs its leading term.
+// For more details, please see Yan.
+//
+// Yan, Thomas. "The geobucket data structure for polynomials." Journal of Symbolic Computation 25.3 (1998): 285-293.
+type geobucket[K Field[K]] struct {
+	field K
+	// compare compares the IDs of monomials by the monomial order.
+	compare func(x, y wordID) int
+	zero    K
+	// buckets[i] is a sorted term vector of at most geobucketBase^(i+1) terms.
+	// Different buckets may contain the same monomial.
+	// The coefficients are owned by the geobucket, and are modified in place.
+	buckets [][]term[K]
+	// lead is the index of the bucket whose first term is the leading term, or -1 if it is not known.
+	lead int
+	// buf is a scratch buffer for building monomials.
+	buf Monomial
+}
+
+// newGeobucket returns a geobucket containing the terms of f.
+func newGeobucket[K Field[K]](f *Polynomial[K]) *geobucket[K] {
+	g := &geobucket[K]{field: f.field, compare: compareWords(f.order), zero: f.field.NewZero(), lead: -1}
+	v := termVector(f, false)
+	for i := range v {
+		v[i].c = g.field.NewZero().Add(g.zero, v[i].c)
+	}
+	g.insert(v)
+	return g
+}
+
+// leadingTerm returns the leading term of g, or false if g is zero.
+// The terms of the leading monomial in all buckets are combined into one, and those that cancel are removed.
+func (g *geobucket[K]) leadingTerm() (term[K], bool) {
+	if g.lead != -1 {
+		return g.buckets[g.lead][0], true
+	}
+	for {
+		lead := -1
+		for i, b := range g.buckets {
+			if len(b) == 0 {
+				continue
+			}
+			if lead == -1 {
+				lead = i
+				continue
+			}
+			switch cmp := g.compare(b[0].w, g.buckets[lead][0].w); {
+			case cmp > 0:
+				lead = i
+			case cmp == 0:
+				t := &g.buckets[lead][0]
+				t.c = t.c.Add(t.c, b[0].c)
+				g.buckets[i] = b[1:]
+			}
+		}
+		if lead == -1 {
+			return term[K]{}, false
+		}
+		if t := g.buckets[lead][0]; !t.c.Equal(g.zero) {
+			g.lead = lead
+			return t, true
+		}
+		g.buckets[lead] = g.buckets[lead][1:]
+	}
+}
+
+// pop removes the leading term of g, which must have been found by leadingTerm.
+func (g *geobucket[K]) pop() {
+	g.buckets[g.lead] = g.buckets[g.lead][1:]
+	g.lead = -1
+}
+
+// sub subtracts c*left*x*right from g, where x is a sorted term vector.
+func (g *geobucket[K]) sub(c K, left Monomial, x []term[K], right Monomial) {
+	if len(x) == 0 {
+		return
+	}
+	neg := g.field.NewZero().Sub(g.zero, c)
+	// Multiplying by monomials preserves the monomial order, so the product is also sorted.
+	v := make([]term[K], len(x))
+	for i, t := range x {
+		g.buf = append(append(append(g.buf[:0], left...), t.w.monomial()...), right...)
+		v[i] = term[K]{c: g.field.NewZero().Mul(neg, t.c), w: intern(g.buf)}
+	}
+	g.insert(v)
+}
+
+// mulScalar multiplies g by scalar.
+func (g *geobucket[K]) mulScalar(scalar K) {
+	for _, b := range g.buckets {
+		for i := range b {
+			b[i].c = b[i].c.Mul(scalar, b[i].c)
+		}
+	}
+}
+
+// insert adds the sorted term vector v to g, merging it with the buckets of comparable length.
+func (g *geobucket[K]) insert(v []term[K]) {
+	g.lead = -1
+	i, capacity := 0, geobucketBase
+	for len(v) > capacity {
+		i, capacity = i+1, capacity*geobucketBase
+	}
+	for {
+		for i >= len(g.buckets) {
+			g.buckets = append(g.buckets, nil)
+		}
+		v = g.merge(g.buckets[i], v)
+		if len(v) <= capacity {
+			g.buckets[i] = v
+			return
+		}
+		g.buckets[i] = nil
+		i, capacity = i+1, capacity*geobucketBase
+	}
+}
+
+// merge returns the sum of the sorted term vectors x and y, whose coefficients are owned by g.
+func (g *geobucket[K]) merge(x, y []term[K]) []term[K] {
+	if len(x) == 0 {
+		return y
+	}
+	z := make([]term[K], 0, len(x)+len(y))
+	for len(x) > 0 && len(y) > 0 {
+		switch cmp := g.compare(x[0].w, y[0].w); {
+		case cmp > 0:
+			z, x = append(z, x[0]), x[1:]
+		case cmp < 0:
+			z, y = append(z, y[0]), y[1:]
+		default:
+			c := x[0].c.Add(x[0].c, y[0].c)
+			if !c.Equal(g.zero) {
+				z = append(z, term[K]{c: c, w: x[0].w})
+			}
+			x, y = x[1:], y[1:]
+		}
+	}
+	z = append(z, x...)
+	return append(z, y...)
+}
@@ -0,0 +1,67 @@
+package nag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGeobucket(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "z": 1}
+	type sub struct {
+		c           int64
+		left, right Monomial
+		x           string
+	}
+	tests := []struct {
+		f     string
+		subs  []sub
+		scale int64
+	}{
+		{f: "0", subs: []sub{{c: 1, x: "x + y"}}},
+		{f: "x^2 + y", subs: []sub{{c: 1, x: "x^2 + y"}}},
+		{f: "x^2 + y", subs: []sub{{c: 2, left: Monomial{1}, x: "x - z", right: Monomial{2}}, {c: -3, x: "zxy + 5"}}, scale: 3},
+		// Many short additions overflow the buckets into longer ones.
+		{f: "xyz + zyx + y^3", subs: []sub{
+			{c: 1, x: "x + y + z"}, {c: 1, left: Monomial{3}, x: "x + y + z"}, {c: 1, x: "y + z"}, {c: 1, right: Monomial{1}, x: "x + y"},
+			{c: -1, x: "x + y + z"}, {c: 7, left: Monomial{2, 2}, x: "x^2 + xy + yx + y^2 + z"}, {c: 1, x: "xyz + zyx"}, {c: 1, x: "-y^3"},
+		}, scale: -2},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			f := parseMust(variables, Deglex, test.f)
+			g := newGeobucket(f)
+			want := parseMust(variables, Deglex, test.f)
+			for _, s := range test.subs {
+				x := parseMust(variables, Deglex, s.x)
+				g.sub(NewRat(s.c, 1), s.left, termVector(x, false), s.right)
+				want.add(-1, NewRat(s.c, 1), s.left, x, s.right)
+			}
+			if test.scale != 0 {
+				g.mulScalar(NewRat(test.scale, 1))
+				want.mulScalar(NewRat(test.scale, 1), want)
+			}
+			if !f.Equal(parseMust(variables, Deglex, test.f)) {
+				t.Errorf("f modified: %v", f)
+			}
+
+			got := NewPolynomial(NewRat(0, 1), Deglex)
+			var prev Monomial
+			for {
+				lt, ok := g.leadingTerm()
+				if !ok {
+					break
+				}
+				if got.Len() > 0 && Deglex(lt.w.monomial(), prev) >= 0 {
+					t.Fatalf("%v is not smaller than %v", lt.w.monomial(), prev)
+				}
+				got.addInterned(1, lt.c, lt.w)
+				prev = lt.w.monomial()
+				g.pop()
+			}
+			if !got.Equal(want) {
+				t.Errorf("got %v want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package nag
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Homogenize returns the homogenization of p with respect to the symbol h.
+// Each term of p is multiplied on the left by a power of h, so that all terms have the same degree as the leading term of highest degree.
+// The symbol h is assumed to commute with all other symbols, and therefore is always placed at the left end of a monomial.
+// Homogenize panics if p already contains h.
+func Homogenize[K Field[K]](p *Polynomial[K], h Symbol) *Polynomial[K] {
+	d := degree(p)
+	z := NewPolynomial(p.field, p.order)
+	z.SymbolStringer = p.SymbolStringer
+	for c, w := range p.Terms() {
+		if slices.Contains(w, h) {
+			panic(fmt.Sprintf("symbol %d in polynomial %v", h, p))
+		}
+		hw := make(Monomial, 0, d)
+		for range d - len(w) {
+			hw = append(hw, h)
+		}
+		hw = append(hw, w...)
+		z.addTerm(1, PolynomialTerm[K]{Coefficient: c, Monomial: hw})
+	}
+	return z
+}
+
+// Dehomogenize returns the polynomial obtained by substituting the symbol h in p with one.
+func Dehomogenize[K Field[K]](p *Polynomial[K], h Symbol) *Polynomial[K] {
+	z := NewPolynomial(p.field, p.order)
+	z.SymbolStringer = p.SymbolStringer
+	for c, w := range p.Terms() {
+		w = slices.DeleteFunc(slices.Clone(w), func(s Symbol) bool { return s == h })
+		z.addTerm(1, PolynomialTerm[K]{Coefficient: c, Monomial: w})
+	}
+	return z
+}
+
+// BuchbergerHomogenized returns the Gröbner basis of the ideal g by way of homogenization.
+// The polynomials in g are homogenized with the symbol h, which must not appear in g, and together with the commutators hx-xh for every symbol x in g, form a homogeneous ideal.
+// The basis of this homogeneous ideal is computed with [BuchbergerHomogeneous] up to degree maxDeg, and then dehomogenized and interreduced.
+// Consequently, unlike [Buchberger], the computation is bounded by degree, with maxDeg limiting the degree of the homogenized polynomials.
+// The returned basis is complete if the basis of the homogeneous ideal is complete.
+//
+// For the dehomogenized basis to be a Gröbner basis of g, the symbol h must be smaller than all other symbols, and the order of g must compare monomials with fewer h's as larger, such as [Deglex].
+// Under such an order, the leading monomial of the dehomogenization of a homogenized polynomial is the dehomogenization of its leading monomial.
+// For more details, please see Li Huishi.
+//
+// Li, Huishi. "Gröbner bases in ring theory." World Scientific, 2012.
+func BuchbergerHomogenized[K Field[K]](g []*Polynomial[K], h Symbol, maxDeg int) (basis []*Polynomial[K], complete bool) {
+	basis, complete, _, _ = BuchbergerHomogenizedContext(context.Background(), g, h, maxDeg, nil)
+	return basis, complete
+}
+
+// BuchbergerHomogenizedContext is like [BuchbergerHomogenized], but stops early when ctx is done, and reports statistics of the computation of the homogeneous basis.
+// Upon cancellation, BuchbergerHomogenizedContext returns the dehomogenization of the partial basis computed so far together with the context's error.
+// A nil opts is equivalent to a zero [Options].
+func BuchbergerHomogenizedContext[K Field[K]](ctx context.Context, g []*Polynomial[K], h Symbol, maxDeg int, opts *Options) (basis []*Polynomial[K], complete bool, stats Stats, err error) {
+	hg := homogenizeIdeal(g, h)
+	hBasis, complete, stats, err := BuchbergerHomogeneousWithOptions(ctx, hg, maxDeg, opts)
+
+	for _, f := range hBasis {
+		f = Dehomogenize(f, h)
+		if f.m.Len() == 0 {
+			continue
+		}
+		basis = append(basis, f)
+	}
+	if err != nil {
+		return monicSorted(basis), complete, stats, err
+	}
+	basis, _, err = interreduceDone(ctx.Done(), basis, nil)
+	return monicSorted(basis), complete, stats, err
+}
+
+// homogenizeIdeal returns the homogenization of g with respect to h, together with the commutators hx-xh for every symbol x in g.
+func homogenizeIdeal[K Field[K]](g []*Polynomial[K], h Symbol) []*Polynomial[K] {
+	hg := make([]*Polynomial[K], 0, len(g))
+	var symbols []Symbol
+	for _, f := range g {
+		hg = append(hg, Homogenize(f, h))
+		for _, w := range f.Terms() {
+			symbols = append(symbols, w...)
+		}
+	}
+	slices.Sort(symbols)
+	symbols = slices.Compact(symbols)
+
+	for _, x := range symbols {
+		f := g[0]
+		one := f.field.NewOne()
+		commutator := NewPolynomial(f.field, f.order,
+			PolynomialTerm[K]{Coefficient: one, Monomial: Monomial{x, h}},
+			PolynomialTerm[K]{Coefficient: f.field.NewZero().Sub(f.field.NewZero(), one), Monomial: Monomial{h, x}})
+		commutator.SymbolStringer = f.SymbolStringer
+		hg = append(hg, commutator)
+	}
+	return hg
+}
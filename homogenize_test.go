@@ -0,0 +1,148 @@
+package nag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHomogenize(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "h": 1}
+	tests := []struct {
+		p           string
+		homogeneous string
+	}{
+		{p: "x^2 - y", homogeneous: "x^2 - hy"},
+		{p: "xyx - y + 2", homogeneous: "xyx - h^2y + 2h^3"},
+		{p: "xy - yx", homogeneous: "xy - yx"},
+		{p: "3", homogeneous: "3"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			p := parseMust(variables, Deglex, test.p)
+			hp := Homogenize(p, variables["h"])
+			if want := parseMust(variables, Deglex, test.homogeneous); !hp.Equal(want) {
+				t.Errorf("got %v want %v", hp, want)
+			}
+			if !homogeneous(hp) {
+				t.Errorf("%v", hp)
+			}
+			if dp := Dehomogenize(hp, variables["h"]); !dp.Equal(p) {
+				t.Errorf("got %v want %v", dp, p)
+			}
+		})
+	}
+}
+
+func TestHomogenizeRoundTrip(t *testing.T) {
+	for i, test := range buchbergerTests() {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			polys := append(append([]*Polynomial[*Rat]{}, test.ideal...), test.basis...)
+			// Use a symbol that does not appear in the polynomials.
+			var h Symbol
+			for _, p := range polys {
+				for _, w := range p.Terms() {
+					for _, s := range w {
+						h = max(h, s+1)
+					}
+				}
+			}
+
+			for j, p := range polys {
+				hp := Homogenize(p, h)
+				if !homogeneous(hp) || degree(hp) != degree(p) || hp.Len() != p.Len() {
+					t.Errorf("%d %v %v", j, p, hp)
+				}
+				if dp := Dehomogenize(hp, h); !dp.Equal(p) {
+					t.Errorf("%d got %v want %v", j, dp, p)
+				}
+				// A homogeneous polynomial with a term free of h is the homogenization of its dehomogenization.
+				if hdp := Homogenize(Dehomogenize(hp, h), h); !hdp.Equal(hp) {
+					t.Errorf("%d got %v want %v", j, hdp, hp)
+				}
+			}
+		})
+	}
+}
+
+func TestHomogenizeIdeal(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "h": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")}
+	want := []string{"x^2 - hy", "xy - hx", "yh - hy", "xh - hx"}
+	hg := homogenizeIdeal(ideal, variables["h"])
+	if len(hg) != len(want) {
+		t.Fatalf("%d %v", len(hg), hg)
+	}
+	for i := range hg {
+		if w := parseMust(variables, Deglex, want[i]); !hg[i].Equal(w) {
+			t.Errorf("%d got %v want %v", i, hg[i], w)
+		}
+	}
+}
+
+func TestBuchbergerHomogenized(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "h": 1}
+	tests := []struct {
+		ideal    []*Polynomial[*Rat]
+		maxDeg   int
+		basis    []*Polynomial[*Rat]
+		complete bool
+	}{
+		{
+			ideal:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")},
+			maxDeg:   2,
+			basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "xy - x"), parseMust(variables, Deglex, "x^2 - y")},
+			complete: false,
+		},
+		{
+			ideal:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")},
+			maxDeg:   4,
+			basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "y^2 - y"), parseMust(variables, Deglex, "yx - x"), parseMust(variables, Deglex, "xy - x"), parseMust(variables, Deglex, "x^2 - y")},
+			complete: true,
+		},
+		{
+			ideal:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "xyx - y"), parseMust(variables, Deglex, "y^2 - 1")},
+			maxDeg:   4,
+			basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "y^2 - 1"), parseMust(variables, Deglex, "xyx - y")},
+			complete: false,
+		},
+		{
+			ideal:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "xyx - y"), parseMust(variables, Deglex, "y^2 - 1")},
+			maxDeg:   8,
+			basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "y^2 - 1"), parseMust(variables, Deglex, "xyx - y")},
+			complete: true,
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			basis, complete := BuchbergerHomogenized(test.ideal, variables["h"], test.maxDeg)
+			if len(basis) != len(test.basis) {
+				t.Fatalf("%d %v", len(basis), basis)
+			}
+			for i := range basis {
+				if !basis[i].Equal(test.basis[i]) {
+					t.Errorf("%d %v", i, basis[i])
+				}
+			}
+			if complete != test.complete {
+				t.Errorf("got %v want %v", complete, test.complete)
+			}
+
+			// A complete basis should be the same as that of the Buchberger algorithm.
+			if !complete {
+				return
+			}
+			want, _ := Buchberger(test.ideal, 1000)
+			if len(basis) != len(want) {
+				t.Fatalf("%v %v", basis, want)
+			}
+			for i := range basis {
+				if !basis[i].Equal(want[i]) {
+					t.Errorf("%d %v %v", i, basis[i], want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package nag
+
+import (
+	"hash/maphash"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// A wordID identifies an interned monomial, see [intern].
+// Equal monomials have the same ID, so that comparing and hashing IDs replaces comparing and hashing monomials.
+// An ID keeps its monomial alive, and the monomial is removed from the table once no ID of it remains.
+type wordID struct {
+	p *word
+}
+
+// A word is an interned monomial, together with its cached degree.
+type word struct {
+	w      Monomial
+	degree int
+}
+
+// wordShards is the number of shards of the table, which reduces lock contention among the workers of [BuchbergerWithOptions].
+const wordShards = 64
+
+// words is the interning table of monomials.
+// The table holds its words weakly, so that words no longer used by any polynomial are reclaimed by the garbage collector.
+// Its memory therefore grows with the number of distinct monomials in use, rather than ever created.
+var words = struct {
+	seed   maphash.Seed
+	shards [wordShards]wordShard
+}{seed: maphash.MakeSeed()}
+
+// A wordShard maps the keys of monomials, see [Monomial.key], to their words.
+type wordShard struct {
+	sync.Mutex
+	m map[string]weak.Pointer[word]
+}
+
+// intern returns the ID of w, which is shared among all polynomials.
+// Polynomials store IDs, so that equal monomials in different polynomials share the same memory, and copying a polynomial does not allocate monomials.
+// The interned monomial does not alias w, so that w may be a reused buffer.
+func intern(w Monomial) wordID {
+	var buf [64]byte
+	b := w.appendKey(buf[:0])
+	shard := &words.shards[maphash.Bytes(words.seed, b)%wordShards]
+
+	shard.Lock()
+	defer shard.Unlock()
+	if wp, ok := shard.m[string(b)]; ok {
+		if p := wp.Value(); p != nil {
+			return wordID{p: p}
+		}
+	}
+	if shard.m == nil {
+		shard.m = make(map[string]weak.Pointer[word])
+	}
+	p := &word{w: append(make(Monomial, 0, len(w)), w...), degree: len(w)}
+	wp := weak.Make(p)
+	key := string(b)
+	shard.m[key] = wp
+	runtime.AddCleanup(p, shard.remove, wordEntry{key: key, wp: wp})
+	return wordID{p: p}
+}
+
+// A wordEntry is an entry of a [wordShard].
+type wordEntry struct {
+	key string
+	wp  weak.Pointer[word]
+}
+
+// remove removes the entry e after its word is reclaimed.
+// The entry may have been replaced by a new word of the same monomial, in which case it is kept.
+func (shard *wordShard) remove(e wordEntry) {
+	shard.Lock()
+	defer shard.Unlock()
+	if shard.m[e.key] == e.wp {
+		delete(shard.m, e.key)
+	}
+}
+
+// word returns the interned monomial of id.
+func (id wordID) word() *word {
+	return id.p
+}
+
+// monomial returns the interned monomial of id.
+// The returned monomial has no spare capacity, so that appending to it does not modify the interned monomial.
+// It must not be modified otherwise.
+func (id wordID) monomial() Monomial {
+	return id.p.w
+}
+
+// compareWords returns a comparison of IDs by the monomial order.
+func compareWords(order Order) func(x, y wordID) int {
+	return func(x, y wordID) int {
+		if x == y {
+			return 0
+		}
+		return order(x.monomial(), y.monomial())
+	}
+}
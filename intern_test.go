@@ -0,0 +1,138 @@
+package nag
+
+import (
+	"fmt"
+	"hash/maphash"
+	"runtime"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestIntern(t *testing.T) {
+	tests := []struct {
+		w Monomial
+	}{
+		{w: Monomial{}},
+		{w: Monomial{1}},
+		{w: Monomial{1, 2, 2, 1}},
+		{w: Monomial{300, 1 << 20, 0}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			buf := append(Monomial{}, test.w...)
+			id := intern(buf)
+			x := id.monomial()
+			if !monomialEq(x, test.w) {
+				t.Fatalf("got %v want %v", x, test.w)
+			}
+			if d := id.word().degree; d != len(test.w) {
+				t.Errorf("degree: got %d want %d", d, len(test.w))
+			}
+			if len(buf) != 0 && unsafe.SliceData(x) == unsafe.SliceData(buf) {
+				t.Errorf("interned monomial aliases its buffer")
+			}
+			if cap(x) != len(x) {
+				t.Errorf("interned monomial has spare capacity %d > %d", cap(x), len(x))
+			}
+			// Modifying the buffer does not affect the interned monomial.
+			for j := range buf {
+				buf[j]++
+			}
+			if !monomialEq(x, test.w) {
+				t.Errorf("got %v want %v", x, test.w)
+			}
+		})
+	}
+}
+
+func TestInternShared(t *testing.T) {
+	x := intern(Monomial{7, 8, 9})
+	if y := intern(Monomial{7, 8, 9}); x != y {
+		t.Errorf("equal monomials have different IDs %v %v", x.monomial(), y.monomial())
+	}
+	if y := intern(Monomial{7, 8}); x == y {
+		t.Errorf("different monomials have the same ID %v", x.monomial())
+	}
+
+	// Intern many monomials and collect them, which keeps the IDs of monomials in use.
+	for i := range 1 << 13 {
+		intern(Monomial{Symbol(i), Symbol(i >> 8), 1 << 30})
+	}
+	runtime.GC()
+	if y := intern(Monomial{7, 8, 9}); x != y {
+		t.Errorf("got %v want %v", y.monomial(), x.monomial())
+	}
+	if w := x.monomial(); !monomialEq(w, Monomial{7, 8, 9}) {
+		t.Errorf("got %v", w)
+	}
+}
+
+func TestInternReclaim(t *testing.T) {
+	w := Monomial{5, 1 << 29, 5}
+	key := w.key()
+	shard := &words.shards[maphash.String(words.seed, key)%wordShards]
+	inTable := func() bool {
+		shard.Lock()
+		defer shard.Unlock()
+		_, ok := shard.m[key]
+		return ok
+	}
+
+	intern(w)
+	if !inTable() {
+		t.Fatalf("not interned")
+	}
+	// Cleanups run asynchronously after the word is collected.
+	for range 100 {
+		runtime.GC()
+		if !inTable() {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if inTable() {
+		t.Fatalf("unused word not reclaimed")
+	}
+
+	// Interning again gives a new usable ID.
+	if x := intern(w).monomial(); !monomialEq(x, w) {
+		t.Errorf("got %v want %v", x, w)
+	}
+}
+
+func TestPolynomialSharesMonomials(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	x := parseMust(variables, Deglex, "ab+ba-2")
+	y := parseMust(variables, Deglex, "a-b")
+	z := NewPolynomial(x.field, Deglex).Mul(x, y)
+	if want := "-bab+ba^2-ab^2+aba+2b-2a"; z.String() != want {
+		t.Errorf("got %v want %v", z, want)
+	}
+
+	// Set shares the monomials of x instead of copying them.
+	s := NewPolynomial(x.field, Deglex).Set(x)
+	if unsafe.SliceData(s.LeadingTerm().Monomial) != unsafe.SliceData(x.LeadingTerm().Monomial) {
+		t.Errorf("Set copied the monomials")
+	}
+}
+
+func TestQuotientClipsMonomials(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	for _, backend := range []Backend{TreeBackend, GeobucketBackend} {
+		f := parseMust(variables, Deglex, "a^2b^2 + ab")
+		g := []*Polynomial[*Rat]{parseMust(variables, Deglex, "b - 1")}
+		quotient, _, _ := NewReducerBackend(g, backend).divide(nil, [][]Quotient[*Rat]{}, f, -1)
+		// Appending to the monomials of a quotient must not modify the interned monomials they are cut from.
+		for _, q := range quotient[0] {
+			_ = append(q.Left, 7)
+			_ = append(q.Right, 7)
+		}
+		for _, w := range []Monomial{{1, 1, 2, 2}, {1, 1, 2}, {1, 1}, {1, 2}, {1}} {
+			if x := intern(w).monomial(); !monomialEq(x, w) {
+				t.Errorf("%v: got %v want %v", backend, x, w)
+			}
+		}
+	}
+}
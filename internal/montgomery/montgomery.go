@@ -0,0 +1,127 @@
+// Package montgomery implements arithmetic modulo a prime less than 2^63 in machine words, using [Montgomery multiplication].
+// It is shared by the prime field [github.com/fumin/nag/field.Fp], and the prime fields of the modular algorithm in [github.com/fumin/nag].
+// The two cannot share a field type directly, since the field package imports nag.
+//
+// Montgomery, Peter L. "Modular multiplication without trial division." Mathematics of computation 44.170 (1985): 519-521.
+//
+// [Montgomery multiplication]: https://en.wikipedia.org/wiki/Montgomery_modular_multiplication
+package montgomery
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// A Modulus holds the precomputed constants for Montgomery multiplication modulo a prime p.
+// Elements are represented in their Montgomery form x*R mod p, where R = 2^64.
+type Modulus struct {
+	p uint64
+	// pInv is -1/p mod R.
+	pInv uint64
+	// r1 is R mod p, which is the Montgomery form of 1.
+	r1 uint64
+	// r2 is R^2 mod p, which converts an integer into its Montgomery form.
+	r2 uint64
+}
+
+// New returns the modulus for the prime p.
+// New panics if p is not a prime number less than 2^63.
+func New(p uint64) *Modulus {
+	if p >= 1<<63 || !new(big.Int).SetUint64(p).ProbablyPrime(20) {
+		panic(fmt.Sprintf("%d is not a prime less than 2^63", p))
+	}
+	m := &Modulus{p: p}
+	// Montgomery multiplication requires an odd modulus, so GF(2) stores elements as is.
+	if p == 2 {
+		m.r1, m.r2 = 1, 1
+		return m
+	}
+
+	// Compute 1/p mod R by Newton's iteration, each of which doubles the number of correct bits.
+	inv := p
+	for range 5 {
+		inv *= 2 - p*inv
+	}
+	m.pInv = -inv
+	m.r1 = -p % p
+	hi, lo := bits.Mul64(m.r1, m.r1)
+	_, m.r2 = bits.Div64(hi, lo, p)
+	return m
+}
+
+// P returns the prime p.
+func (m *Modulus) P() uint64 { return m.p }
+
+// One returns the Montgomery form of 1.
+func (m *Modulus) One() uint64 { return m.r1 }
+
+// redc returns the Montgomery reduction (hi*R + lo)/R mod p, provided that hi*R + lo < p*R.
+func (m *Modulus) redc(hi, lo uint64) uint64 {
+	q := lo * m.pInv
+	qHi, qLo := bits.Mul64(q, m.p)
+	_, carry := bits.Add64(lo, qLo, 0)
+	// Since p < 2^63, the sum is less than 2p and does not overflow.
+	t := hi + qHi + carry
+	if t >= m.p {
+		t -= m.p
+	}
+	return t
+}
+
+// Add returns x+y mod p.
+func (m *Modulus) Add(x, y uint64) uint64 {
+	// Since p < 2^63, the sum does not overflow.
+	v := x + y
+	if v >= m.p {
+		v -= m.p
+	}
+	return v
+}
+
+// Sub returns x-y mod p.
+func (m *Modulus) Sub(x, y uint64) uint64 {
+	if x >= y {
+		return x - y
+	}
+	return x + m.p - y
+}
+
+// Mul returns the Montgomery product x*y/R mod p, which is the Montgomery form of the product of the elements of x and y.
+func (m *Modulus) Mul(x, y uint64) uint64 {
+	if m.p == 2 {
+		return x & y
+	}
+	hi, lo := bits.Mul64(x, y)
+	return m.redc(hi, lo)
+}
+
+// Inv returns the Montgomery form of the inverse of the element of x.
+// The inverse is computed by Fermat's little theorem, x^(p-2) = 1/x.
+// Inv panics if x is zero.
+func (m *Modulus) Inv(x uint64) uint64 {
+	if x == 0 {
+		panic("division by zero")
+	}
+	base, result := x, m.r1
+	for e := m.p - 2; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = m.Mul(result, base)
+		}
+		base = m.Mul(base, base)
+	}
+	return result
+}
+
+// ToMont returns the Montgomery form of x mod p.
+func (m *Modulus) ToMont(x uint64) uint64 {
+	return m.Mul(x%m.p, m.r2)
+}
+
+// FromMont returns the integer in the range [0, p) represented by the Montgomery form x.
+func (m *Modulus) FromMont(x uint64) uint64 {
+	if m.p == 2 {
+		return x
+	}
+	return m.redc(0, x)
+}
@@ -0,0 +1,236 @@
+package nag
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A macaulay is a sparse Macaulay matrix, whose rows are homogeneous polynomials of the same degree.
+// Its columns are the monomials of the rows, sorted in descending order, so that the leading term of a row is its first non-zero entry.
+// This is the linear algebra formulation of polynomial division used by the F4 algorithm.
+//
+// Faugère, Jean-Charles. "A new efficient algorithm for computing Gröbner bases (F4)." Journal of pure and applied algebra 139.1-3 (1999): 61-88.
+type macaulay[K Field[K]] struct {
+	field K
+	order Order
+	cols  []Monomial
+	// colIndex maps the IDs of the monomials in cols to their columns.
+	colIndex map[wordID]int
+	// pivots are rows with leading coefficient one, indexed by their leading column.
+	pivots map[int]sparseRow[K]
+}
+
+// A sparseRow is a row in a Macaulay matrix, whose non-zero entries are at cols.
+// The columns are in ascending order.
+type sparseRow[K Field[K]] struct {
+	cols    []int
+	entries []K
+}
+
+// newMacaulay returns the Macaulay matrix for reducing the polynomials in targets by basis.
+// All polynomials in targets must be homogeneous of the same degree.
+// Similar to the symbolic preprocessing of the F4 algorithm, the matrix contains a reducer row for each of its monomials that is divisible by a leading monomial in basis.
+// It checks before adding each reducer row whether done is closed, and if so returns errCanceled.
+func newMacaulay[K Field[K]](done <-chan struct{}, targets, basis []*Polynomial[K]) (*macaulay[K], error) {
+	mat := &macaulay[K]{field: targets[0].field.NewZero(), order: targets[0].order, pivots: make(map[int]sparseRow[K])}
+
+	// Collect monomials and reducers.
+	seen := make(map[wordID]bool)
+	var queue []wordID
+	push := func(f *Polynomial[K]) {
+		for w := range f.m.All() {
+			if !seen[w] {
+				seen[w] = true
+				queue = append(queue, w)
+			}
+		}
+	}
+	for _, f := range targets {
+		push(f)
+	}
+	var reducers []*Polynomial[K]
+	for len(queue) > 0 {
+		select {
+		case <-done:
+			return nil, errCanceled
+		default:
+		}
+		w := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		r := reducerRow(w.monomial(), basis, mat.field)
+		if r == nil {
+			continue
+		}
+		reducers = append(reducers, r)
+		push(r)
+	}
+
+	// Sort columns.
+	ids := make([]wordID, 0, len(seen))
+	for w := range seen {
+		ids = append(ids, w)
+	}
+	compare := compareWords(mat.order)
+	slices.SortFunc(ids, func(x, y wordID) int { return compare(y, x) })
+	mat.cols = make([]Monomial, len(ids))
+	mat.colIndex = make(map[wordID]int, len(ids))
+	for c, w := range ids {
+		mat.cols[c] = w.monomial()
+		mat.colIndex[w] = c
+	}
+
+	for _, r := range reducers {
+		row := mat.row(r)
+		mat.pivots[row.cols[0]] = row
+	}
+	return mat, nil
+}
+
+// reducerRow returns the monic multiple left*g*right of a polynomial g in basis, whose leading monomial is w.
+// It returns nil if w is not divisible by any leading monomial in basis.
+func reducerRow[K Field[K]](w Monomial, basis []*Polynomial[K], r0 K) *Polynomial[K] {
+	for _, g := range basis {
+		lt := g.LeadingTerm()
+		i := monomialIndex(w, lt.Monomial)
+		if i == -1 {
+			continue
+		}
+		r := NewPolynomial(g.field, g.order)
+		r.add(1, r0.Inv(lt.Coefficient), w[:i], g, w[i+len(lt.Monomial):])
+		return r
+	}
+	return nil
+}
+
+// reduce row reduces the polynomials in fs together by the pivots of the matrix, and returns their remainders.
+// The rows of fs are eliminated in a single pass of Gaussian elimination over the columns.
+// At each column, the first row of fs with an entry there becomes a pivot if the matrix has none, and the pivot eliminates the column from all other rows.
+// Hence, the non-zero remainders are reduced with respect to the pivots as well as to each other, and are added to the matrix as new pivots.
+// The rows of fs are kept sparse throughout the elimination, so that memory grows with their number of non-zero entries instead of the number of columns.
+// It checks before eliminating each column whether done is closed, and if so returns errCanceled.
+func (mat *macaulay[K]) reduce(done <-chan struct{}, fs []*Polynomial[K]) ([]*Polynomial[K], error) {
+	rows := make([]sparseRow[K], len(fs))
+	for k, f := range fs {
+		rows[k] = mat.row(f)
+	}
+	// next are the positions of the first entries of rows whose columns are not yet eliminated.
+	// Eliminating a column only changes entries at larger columns, so the entries before next are final.
+	next := make([]int, len(fs))
+	// leads are the leading columns of the rows that become pivots, or -1 for the other rows.
+	leads := make([]int, len(fs))
+	for k := range leads {
+		leads[k] = -1
+	}
+	for {
+		select {
+		case <-done:
+			return nil, errCanceled
+		default:
+		}
+
+		// Eliminate the smallest column among the entries not yet eliminated.
+		c := -1
+		for k, row := range rows {
+			if next[k] < len(row.cols) && (c == -1 || row.cols[next[k]] < c) {
+				c = row.cols[next[k]]
+			}
+		}
+		if c == -1 {
+			break
+		}
+
+		pivot, ok := mat.pivots[c]
+		if !ok {
+			for k, row := range rows {
+				if leads[k] == -1 && next[k] < len(row.cols) && row.cols[next[k]] == c {
+					leads[k] = c
+					pivot, ok = mat.pivot(row, next[k]), true
+					mat.pivots[c] = pivot
+					break
+				}
+			}
+		}
+
+		for k, row := range rows {
+			if next[k] == len(row.cols) || row.cols[next[k]] != c {
+				continue
+			}
+			if !ok || leads[k] == c {
+				next[k]++
+				continue
+			}
+			rows[k] = mat.eliminate(row, next[k], pivot)
+		}
+	}
+
+	remainders := make([]*Polynomial[K], len(fs))
+	for k, f := range fs {
+		remainders[k] = NewPolynomial(f.field, f.order)
+		remainders[k].SymbolStringer = f.SymbolStringer
+		if leads[k] == -1 {
+			continue
+		}
+		// Entries before the leading column have all been eliminated, since the row would otherwise have become a pivot earlier.
+		row := rows[k]
+		for i, c := range row.cols {
+			remainders[k].addTerm(1, PolynomialTerm[K]{Coefficient: row.entries[i], Monomial: mat.cols[c]})
+		}
+		// Replace the pivot by the fully reduced row.
+		mat.pivots[leads[k]] = mat.pivot(row, 0)
+	}
+	return remainders, nil
+}
+
+// eliminate returns row minus the multiple of pivot that cancels the entry of row at position i, which must be at the leading column of pivot.
+// The entries of row before i are kept as is, and the entries of row are modified.
+func (mat *macaulay[K]) eliminate(row sparseRow[K], i int, pivot sparseRow[K]) sparseRow[K] {
+	zero := mat.field.NewZero()
+	buf := mat.field.NewZero()
+	factor := row.entries[i]
+	z := sparseRow[K]{cols: make([]int, 0, len(row.cols)+len(pivot.cols)), entries: make([]K, 0, len(row.cols)+len(pivot.cols))}
+	z.cols, z.entries = append(z.cols, row.cols[:i]...), append(z.entries, row.entries[:i]...)
+
+	x, y := i+1, 1
+	for x < len(row.cols) || y < len(pivot.cols) {
+		switch {
+		case y == len(pivot.cols) || (x < len(row.cols) && row.cols[x] < pivot.cols[y]):
+			z.cols, z.entries = append(z.cols, row.cols[x]), append(z.entries, row.entries[x])
+			x++
+		case x == len(row.cols) || pivot.cols[y] < row.cols[x]:
+			e := mat.field.NewZero().Sub(zero, buf.Mul(factor, pivot.entries[y]))
+			z.cols, z.entries = append(z.cols, pivot.cols[y]), append(z.entries, e)
+			y++
+		default:
+			e := row.entries[x].Sub(row.entries[x], buf.Mul(factor, pivot.entries[y]))
+			if !e.Equal(zero) {
+				z.cols, z.entries = append(z.cols, row.cols[x]), append(z.entries, e)
+			}
+			x, y = x+1, y+1
+		}
+	}
+	return z
+}
+
+// pivot returns the entries of row from position i onwards, divided by the entry at i.
+func (mat *macaulay[K]) pivot(row sparseRow[K], i int) sparseRow[K] {
+	inv := mat.field.NewZero().Inv(row.entries[i])
+	pivot := sparseRow[K]{cols: slices.Clone(row.cols[i:]), entries: make([]K, 0, len(row.cols)-i)}
+	for _, e := range row.entries[i:] {
+		pivot.entries = append(pivot.entries, mat.field.NewZero().Mul(e, inv))
+	}
+	return pivot
+}
+
+// row returns the sparse row of f.
+func (mat *macaulay[K]) row(f *Polynomial[K]) sparseRow[K] {
+	var row sparseRow[K]
+	for w, c := range f.m.Backward() {
+		col, ok := mat.colIndex[w]
+		if !ok {
+			panic(fmt.Sprintf("monomial %v not in matrix", w.monomial()))
+		}
+		row.cols = append(row.cols, col)
+		row.entries = append(row.entries, mat.field.NewZero().Add(mat.field.NewZero(), c))
+	}
+	return row
+}
@@ -0,0 +1,101 @@
+package nag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestMatrixReduction(t *testing.T) {
+	for i, test := range buchbergerHomogeneousTests() {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			opts := &Options{Reduction: MatrixReduction}
+			basis, complete, _, err := BuchbergerHomogeneousWithOptions(context.Background(), test.ideal, test.maxDeg, opts)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if len(basis) != len(test.basis) {
+				t.Fatalf("%d %v", len(basis), basis)
+			}
+			for i := range basis {
+				if !basis[i].Equal(test.basis[i]) {
+					t.Errorf("%d %v", i, basis[i])
+				}
+			}
+			if complete != test.complete {
+				t.Errorf("got %v want %v", complete, test.complete)
+			}
+		})
+	}
+}
+
+func TestMacaulayReduce(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	basis := []*Polynomial[*Rat]{parseMust(variables, Deglex, "a^2 - b^2")}
+	var targets []*Polynomial[*Rat]
+	for _, s := range []string{"ab - ba", "ab + ba + a^2", "2ab + b^2", "ba - b^2"} {
+		targets = append(targets, parseMust(variables, Deglex, s))
+	}
+
+	// The rows are reduced by each other as well as by the basis.
+	mat, err := newMacaulay(nil, targets, basis)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	remainders, err := mat.reduce(nil, targets)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	want := []string{"-ba", "2ab", "0", "-3/2a^2"}
+	for i := range remainders {
+		if remainders[i].String() != want[i] {
+			t.Errorf("%d got %v want %v", i, remainders[i], want[i])
+		}
+	}
+}
+
+func TestMacaulayReduceCanceled(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	basis := []*Polynomial[*Rat]{parseMust(variables, Deglex, "a^2 - b^2")}
+	targets := []*Polynomial[*Rat]{parseMust(variables, Deglex, "ab - ba"), parseMust(variables, Deglex, "ba - b^2")}
+	mat, err := newMacaulay(nil, targets, basis)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	done := make(chan struct{})
+	close(done)
+	if _, err := mat.reduce(done, targets); err != errCanceled {
+		t.Errorf("%v", err)
+	}
+	if _, err := newMacaulay(done, targets, basis); err != errCanceled {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestMatrixReductionContext(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "aba - bab")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel after a fixed number of reductions, since the braid relation has an infinite Gröbner basis.
+	const cancelAfter = 20
+	opts := &Options{Reduction: MatrixReduction, Observer: func(s Stats) {
+		if s.Reductions >= cancelAfter {
+			cancel()
+		}
+	}}
+
+	basis, complete, _, err := BuchbergerHomogeneousWithOptions(ctx, ideal, math.MaxInt, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("%v", err)
+	}
+	if complete {
+		t.Errorf("complete")
+	}
+	if len(basis) <= len(ideal) {
+		t.Errorf("%v", basis)
+	}
+}
@@ -0,0 +1,383 @@
+package nag
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/fumin/nag/internal/montgomery"
+	"github.com/pkg/errors"
+)
+
+// BuchbergerModular returns the Gröbner basis of the ideal g over the rationals, using a modular algorithm.
+// The Gröbner basis is computed with [Buchberger] over several prime fields GF(p), where arithmetic is done in machine words instead of arbitrary precision rationals.
+// The bases over different primes are combined by the Chinese remainder theorem, and the rational coefficients are recovered by rational reconstruction.
+// Primes for which the leading monomials of the basis disagree with the majority are considered unlucky and discarded.
+// A complete basis is the same as that of [Buchberger].
+//
+// The reconstructed basis is verified over the rationals to be a Gröbner basis, which contains g in its ideal, by reducing g and the S-polynomials of the basis to zero.
+// Similar to the commutative case, the verified basis is the Gröbner basis of g if the primes are lucky, which is true for all but finitely many primes.
+// An incomplete basis cannot be verified, so if the computation over a prime does not complete within maxIter, BuchbergerModular computes over the rationals instead.
+// It also falls back to computing over the rationals if no basis can be reconstructed and verified after a large number of primes.
+// For more details, please see Arnold.
+//
+// Arnold, Elizabeth A. "Modular algorithms for computing Gröbner bases." Journal of Symbolic Computation 35.4 (2003): 403-419.
+func BuchbergerModular(g []*Polynomial[*Rat], maxIter int) (basis []*Polynomial[*Rat], complete bool) {
+	basis, complete, _ = BuchbergerModularContext(context.Background(), g, maxIter, nil)
+	return basis, complete
+}
+
+// BuchbergerModularContext is like [BuchbergerModular], but stops early with the context's error when ctx is done.
+// The options are applied to the computation over each prime field.
+// A nil opts is equivalent to a zero [Options].
+func BuchbergerModularContext(ctx context.Context, g []*Polynomial[*Rat], maxIter int, opts *Options) (basis []*Polynomial[*Rat], complete bool, err error) {
+	// maxPrimes bounds the number of primes before falling back to computing over the rationals.
+	const maxPrimes = 64
+
+	// Zero polynomials do not contribute to the ideal, and are removed so that they do not make every prime look unlucky.
+	nonzero := make([]*Polynomial[*Rat], 0, len(g))
+	for _, f := range g {
+		if f.Len() != 0 {
+			nonzero = append(nonzero, f)
+		}
+	}
+
+	// images are the combined bases over the primes, grouped by their leading monomials.
+	images := make(map[string]*modularImage)
+	// failed is the last candidate that failed verification, which need not be verified again.
+	var failed []*Polynomial[*Rat]
+	for i := range maxPrimes {
+		if len(nonzero) == 0 {
+			break
+		}
+		p := modularPrime(i)
+		gp, ok := reduceModP(nonzero, p)
+		if !ok {
+			continue
+		}
+		basisP, completeP, _, err := BuchbergerWithOptions(ctx, gp, maxIter, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		if !completeP {
+			break
+		}
+
+		key := leadingMonomialsKey(basisP)
+		img, ok := images[key]
+		if !ok {
+			img = &modularImage{modulus: big.NewInt(1)}
+			images[key] = img
+		}
+		img.combine(basisP, p)
+		// Only reconstruct from the image with the most primes, since the other images come from unlucky primes.
+		if !img.majority(images) {
+			continue
+		}
+
+		candidate, ok := img.reconstruct(nonzero[0])
+		if !ok || polynomialsEqual(candidate, failed) {
+			continue
+		}
+		ok, err = verifyModular(ctx.Done(), nonzero, candidate)
+		if err != nil {
+			return nil, false, errors.Wrap(ctx.Err(), "")
+		}
+		if ok {
+			return candidate, true, nil
+		}
+		failed = candidate
+	}
+
+	basis, complete, _, err = BuchbergerWithOptions(ctx, g, maxIter, opts)
+	return basis, complete, err
+}
+
+// modularPrimeMax is the upper bound of the primes in [BuchbergerModular].
+// Primes are less than 2^62, which is within the range of [montgomery.Modulus], so that few primes are needed to reconstruct large coefficients.
+const modularPrimeMax = 1 << 62
+
+// modularPrimes caches the primes of [BuchbergerModular] in decreasing order, since finding them takes a considerable part of small computations.
+var modularPrimes struct {
+	sync.Mutex
+	p []uint64
+}
+
+// modularPrime returns the i'th largest prime less than modularPrimeMax, counting from zero.
+func modularPrime(i int) uint64 {
+	modularPrimes.Lock()
+	defer modularPrimes.Unlock()
+	for len(modularPrimes.p) <= i {
+		n := uint64(modularPrimeMax)
+		if len(modularPrimes.p) > 0 {
+			n = modularPrimes.p[len(modularPrimes.p)-1]
+		}
+		modularPrimes.p = append(modularPrimes.p, prevPrime(n))
+	}
+	return modularPrimes.p[i]
+}
+
+// A modularImage is a basis combined over several primes by the Chinese remainder theorem.
+type modularImage struct {
+	// modulus is the product of the primes.
+	modulus *big.Int
+	// numPrimes is the number of primes.
+	numPrimes int
+	// coefficients are the coefficients of each polynomial in the basis modulo the modulus, keyed by monomial.
+	coefficients []map[string]*big.Int
+	// monomials are the monomials of each polynomial in the basis, in the order of their first appearance.
+	monomials [][]Monomial
+}
+
+// combine combines the basis over the prime p into img.
+func (img *modularImage) combine(basis []*Polynomial[*modP], p uint64) {
+	if img.numPrimes == 0 {
+		img.coefficients = make([]map[string]*big.Int, len(basis))
+		img.monomials = make([][]Monomial, len(basis))
+		for i := range basis {
+			img.coefficients[i] = make(map[string]*big.Int)
+		}
+	}
+
+	bigP := new(big.Int).SetUint64(p)
+	// mInv is the inverse of the modulus modulo p.
+	mInv := new(big.Int).ModInverse(new(big.Int).Mod(img.modulus, bigP), bigP)
+	for i, f := range basis {
+		residues := make(map[string]uint64, f.Len())
+		for c, w := range f.Terms() {
+			if _, ok := img.coefficients[i][w.key()]; !ok {
+				img.coefficients[i][w.key()] = big.NewInt(0)
+				img.monomials[i] = append(img.monomials[i], w)
+			}
+			residues[w.key()] = c.Uint64()
+		}
+		// Combine the residues by the Chinese remainder theorem, where missing residues are zero.
+		for k, x := range img.coefficients[i] {
+			// x = x + modulus * ((r - x) * mInv mod p)
+			t := new(big.Int).SetUint64(residues[k])
+			t.Sub(t, x)
+			t.Mul(t, mInv)
+			t.Mod(t, bigP)
+			x.Add(x, t.Mul(t, img.modulus))
+		}
+	}
+	img.modulus.Mul(img.modulus, bigP)
+	img.numPrimes++
+}
+
+// majority reports whether img has the most primes among images.
+func (img *modularImage) majority(images map[string]*modularImage) bool {
+	for _, other := range images {
+		if other.numPrimes > img.numPrimes {
+			return false
+		}
+	}
+	return true
+}
+
+// reconstruct returns the basis over the rationals by rational reconstruction.
+// The polynomial f0 provides the order and symbols of the returned polynomials.
+func (img *modularImage) reconstruct(f0 *Polynomial[*Rat]) ([]*Polynomial[*Rat], bool) {
+	basis := make([]*Polynomial[*Rat], len(img.coefficients))
+	for i := range img.coefficients {
+		f := NewPolynomial(f0.field.NewZero(), f0.order)
+		f.SymbolStringer = f0.SymbolStringer
+		for _, w := range img.monomials[i] {
+			c, ok := rationalReconstruction(img.coefficients[i][w.key()], img.modulus)
+			if !ok {
+				return nil, false
+			}
+			f.addTerm(1, PolynomialTerm[*Rat]{Coefficient: &Rat{c}, Monomial: w})
+		}
+		basis[i] = f
+	}
+	return basis, true
+}
+
+// verifyModular reports whether candidate is a Gröbner basis, whose ideal contains g.
+// The polynomials in g and the S-polynomials of candidate must reduce to zero by candidate.
+// It checks before each reduction step whether done is closed, and if so returns errCanceled.
+func verifyModular(done <-chan struct{}, g, candidate []*Polynomial[*Rat]) (bool, error) {
+	for _, f := range candidate {
+		if f.Len() == 0 {
+			return false, nil
+		}
+	}
+	if len(candidate) == 0 {
+		for _, f := range g {
+			if f.Len() != 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	reducer := NewReducer(candidate)
+	for _, f := range g {
+		f = NewPolynomial(f.field.NewZero(), f.order).Set(f)
+		_, r, err := reducer.divide(done, nil, f, -1)
+		if err != nil {
+			return false, err
+		}
+		if r.Len() != 0 {
+			return false, nil
+		}
+	}
+
+	// Find the obstructions of candidate after the criteria, in the same way as a checkpoint.
+	var obs []obstruction[*Rat]
+	buf := &Monomial{}
+	for l := 1; l <= len(candidate); l++ {
+		obs = addObstructions(obs, candidate[:l], buf, nil)
+	}
+	r0 := candidate[0].field.NewZero()
+	for _, o := range obs {
+		_, r, err := reducer.divide(done, nil, sPolynomial(o, candidate, r0, nil), -1)
+		if err != nil {
+			return false, err
+		}
+		if r.Len() != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// rationalReconstruction returns the rational a/b such that a = u*b modulo m, and |a|, b <= sqrt(m/2).
+// For more details, please see Wang et al.
+//
+// Wang, Paul S., M. J. T. Guy, and J. H. Davenport. "P-adic reconstruction of rational numbers." ACM SIGSAM Bulletin 16.2 (1982): 2-3.
+func rationalReconstruction(u, m *big.Int) (*big.Rat, bool) {
+	bound := new(big.Int).Rsh(m, 1)
+	bound.Sqrt(bound)
+
+	// Run the extended Euclidean algorithm on m and u, until the remainder is not larger than bound.
+	r0, r1 := new(big.Int).Set(m), new(big.Int).Mod(u, m)
+	t0, t1 := big.NewInt(0), big.NewInt(1)
+	for r1.Cmp(bound) > 0 {
+		q := new(big.Int).Quo(r0, r1)
+		r0, r1 = r1, new(big.Int).Sub(r0, new(big.Int).Mul(q, r1))
+		t0, t1 = t1, new(big.Int).Sub(t0, new(big.Int).Mul(q, t1))
+	}
+	if t1.Sign() == 0 || new(big.Int).Abs(t1).Cmp(bound) > 0 {
+		return nil, false
+	}
+	if new(big.Int).GCD(nil, nil, r1, new(big.Int).Abs(t1)).Cmp(big.NewInt(1)) != 0 {
+		return nil, false
+	}
+	return new(big.Rat).SetFrac(r1, t1), true
+}
+
+// reduceModP returns the polynomials in g with coefficients reduced modulo the prime p.
+// It returns false if a denominator in g is divisible by p, or if a polynomial in g vanishes modulo p, since such a prime is unlucky.
+func reduceModP(g []*Polynomial[*Rat], p uint64) ([]*Polynomial[*modP], bool) {
+	bigP := new(big.Int).SetUint64(p)
+	m := montgomery.New(p)
+	gp := make([]*Polynomial[*modP], 0, len(g))
+	num, den := new(big.Int), new(big.Int)
+	for _, f := range g {
+		fp := NewPolynomial(&modP{m: m}, f.order)
+		fp.SymbolStringer = f.SymbolStringer
+		for c, w := range f.Terms() {
+			den.Mod(c.Denom(), bigP)
+			if den.Sign() == 0 {
+				return nil, false
+			}
+			num.Mod(c.Num(), bigP)
+			x := &modP{m: m, v: m.ToMont(num.Uint64())}
+			x.Div(x, &modP{m: m, v: m.ToMont(den.Uint64())})
+			fp.addTerm(1, PolynomialTerm[*modP]{Coefficient: x, Monomial: w})
+		}
+		if fp.Len() == 0 {
+			return nil, false
+		}
+		gp = append(gp, fp)
+	}
+	return gp, len(gp) != 0
+}
+
+// leadingMonomialsKey returns a string identifying the leading monomials of basis.
+func leadingMonomialsKey[K Field[K]](basis []*Polynomial[K]) string {
+	var b strings.Builder
+	for _, f := range basis {
+		fmt.Fprintf(&b, "%x,", f.LeadingTerm().Monomial.key())
+	}
+	return b.String()
+}
+
+func polynomialsEqual[K Field[K]](x, y []*Polynomial[K]) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !x[i].Equal(y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// prevPrime returns the largest prime less than n.
+func prevPrime(n uint64) uint64 {
+	for n--; n > 2; n-- {
+		if new(big.Int).SetUint64(n).ProbablyPrime(20) {
+			return n
+		}
+	}
+	panic(fmt.Sprintf("no prime less than %d", n))
+}
+
+// A modP is an element of the prime field GF(p) of the modular algorithm.
+// It shares the arithmetic of [github.com/fumin/nag/field.Fp] through [montgomery.Modulus], since field.Fp itself cannot be used, as the field package imports nag.
+type modP struct {
+	m *montgomery.Modulus
+	// v is the Montgomery form of the element.
+	v uint64
+}
+
+// NewZero returns the additive identity 0.
+func (x *modP) NewZero() *modP { return &modP{m: x.m} }
+
+// NewOne returns the multiplicative identity 1.
+func (x *modP) NewOne() *modP { return &modP{m: x.m, v: x.m.One()} }
+
+// Uint64 returns the integer representation of x, which is in the range [0, p).
+func (x *modP) Uint64() uint64 { return x.m.FromMont(x.v) }
+
+// Equal reports whether x and y are equal.
+func (x *modP) Equal(y *modP) bool { return x.v == y.v }
+
+// Add sets z to the sum x+y and returns z.
+func (z *modP) Add(x, y *modP) *modP {
+	z.m, z.v = x.m, x.m.Add(x.v, y.v)
+	return z
+}
+
+// Sub sets z to the difference x-y and returns z.
+func (z *modP) Sub(x, y *modP) *modP {
+	z.m, z.v = x.m, x.m.Sub(x.v, y.v)
+	return z
+}
+
+// Mul sets z to the product x*y and returns z.
+func (z *modP) Mul(x, y *modP) *modP {
+	z.m, z.v = x.m, x.m.Mul(x.v, y.v)
+	return z
+}
+
+// Div sets z to the quotient x/y and returns z.
+func (z *modP) Div(x, y *modP) *modP {
+	z.m, z.v = x.m, x.m.Mul(x.v, x.m.Inv(y.v))
+	return z
+}
+
+// Inv sets z to 1/x and returns z.
+func (z *modP) Inv(x *modP) *modP {
+	z.m, z.v = x.m, x.m.Inv(x.v)
+	return z
+}
+
+// String returns the integer representation of x.
+func (x *modP) String() string { return fmt.Sprintf("%d", x.Uint64()) }
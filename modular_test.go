@@ -0,0 +1,166 @@
+package nag
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestBuchbergerModular(t *testing.T) {
+	testBuchbergerCases(t, false, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+		return BuchbergerModular(test.ideal, test.maxiter)
+	})
+}
+
+func TestBuchbergerModularUnlucky(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	p0, p1 := modularPrime(0), modularPrime(1)
+	tests := []struct {
+		ideal []string
+	}{
+		// The first prime divides a denominator.
+		{ideal: []string{fmt.Sprintf("ab - 1/%dba", p0), "b^2 - 3a"}},
+		// The leading coefficient vanishes modulo the first prime, which changes the leading monomials.
+		{ideal: []string{fmt.Sprintf("%dab - ba + b", p0), "b^2 - 3a"}},
+		// The leading coefficient vanishes modulo the second prime, after the first prime already yields the basis.
+		{ideal: []string{fmt.Sprintf("%dab - ba + b", p1), "b^2 - 3a"}},
+		// The leading coefficients vanish modulo the first and second primes respectively, so that each prime gives a different unlucky basis.
+		{ideal: []string{fmt.Sprintf("%dab - ba + b", p0), fmt.Sprintf("%db^2 - 3a + b", p1)}},
+		// The polynomial vanishes modulo the first prime.
+		{ideal: []string{fmt.Sprintf("%dab - %dba", p0, 2*p0), "b^2 - 3a"}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := make([]*Polynomial[*Rat], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = parseMust(variables, Deglex, s)
+			}
+			want, wantComplete := Buchberger(ideal, 100)
+			if !wantComplete {
+				t.Fatalf("%v", want)
+			}
+			basis, complete := BuchbergerModular(ideal, 100)
+			if !polynomialsEqual(basis, want) {
+				t.Errorf("got %v want %v", basis, want)
+			}
+			if !complete {
+				t.Errorf("incomplete")
+			}
+		})
+	}
+}
+
+func TestBuchbergerModularIncomplete(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	tests := []struct {
+		ideal   []string
+		maxIter int
+	}{
+		{ideal: []string{"aba - 2bab + 3a"}, maxIter: 10},
+		{ideal: []string{"ab^2 - 1/2ba", "a^2b - 3b"}, maxIter: 10},
+		{ideal: []string{"ab^2 - 1/2ba", "a^2b - 3b"}, maxIter: 30},
+		{ideal: []string{"123456789ab - 987654321/1000003ba + 1", "b^2 - 7/11a"}, maxIter: 30},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := make([]*Polynomial[*Rat], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = parseMust(variables, Deglex, s)
+			}
+			want, wantComplete := Buchberger(ideal, test.maxIter)
+			basis, complete := BuchbergerModular(ideal, test.maxIter)
+			if !polynomialsEqual(basis, want) {
+				t.Errorf("got %v want %v", basis, want)
+			}
+			if complete != wantComplete {
+				t.Errorf("got %v want %v", complete, wantComplete)
+			}
+		})
+	}
+}
+
+// BenchmarkModular compares [Buchberger] and [BuchbergerModular] on the Katsura-4 system, eliminating all but one variable.
+// The coefficients swell over the rationals, whereas the modular algorithm needs only a few primes.
+func BenchmarkModular(b *testing.B) {
+	variables := map[string]Symbol{"a": 4, "b": 3, "c": 2, "d": 1}
+	order := BlockOrder(Deglex, []Symbol{4, 3, 2}, []Symbol{1})
+	var ideal []*Polynomial[*Rat]
+	for _, s := range []string{"a + 2b + 2c + 2d - 1", "a^2 + 2b^2 + 2c^2 + 2d^2 - a", "2ab + 2bc + 2cd - b", "b^2 + 2ac + 2bd - c", "ab - ba", "ac - ca", "ad - da", "bc - cb", "bd - db", "cd - dc"} {
+		ideal = append(ideal, parseMust(variables, order, s))
+	}
+	b.Run("Buchberger", func(b *testing.B) {
+		for b.Loop() {
+			if _, complete := Buchberger(ideal, 5000); !complete {
+				b.Fatalf("incomplete")
+			}
+		}
+	})
+	b.Run("Modular", func(b *testing.B) {
+		for b.Loop() {
+			if _, complete := BuchbergerModular(ideal, 5000); !complete {
+				b.Fatalf("incomplete")
+			}
+		}
+	})
+}
+
+func TestReduceModP(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2}
+	tests := []struct {
+		ideal []string
+		ok    bool
+	}{
+		{ideal: []string{"ab - 2ba", "b^2 - 1/3a"}, ok: true},
+		// The denominator is divisible by p.
+		{ideal: []string{"ab - 2ba", "b^2 - 1/2147483647a"}, ok: false},
+		// The second polynomial vanishes modulo p.
+		{ideal: []string{"ab - 2ba", "2147483647b^2 - 4294967294a"}, ok: false},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := make([]*Polynomial[*Rat], len(test.ideal))
+			for j, s := range test.ideal {
+				ideal[j] = parseMust(variables, Deglex, s)
+			}
+			gp, ok := reduceModP(ideal, 2147483647)
+			if ok != test.ok {
+				t.Fatalf("got %v want %v", ok, test.ok)
+			}
+			if ok && len(gp) != len(ideal) {
+				t.Errorf("%d %v", len(gp), gp)
+			}
+		})
+	}
+}
+
+func TestRationalReconstruction(t *testing.T) {
+	m := new(big.Int).Mul(big.NewInt(2147483647), big.NewInt(2147483629))
+	tests := []struct {
+		x  *big.Rat
+		ok bool
+	}{
+		{x: big.NewRat(0, 1), ok: true},
+		{x: big.NewRat(-5, 576), ok: true},
+		{x: big.NewRat(123456, 789011), ok: true},
+		{x: big.NewRat(-1000000007, 999999937), ok: true},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			// u = num/denom modulo m.
+			u := new(big.Int).ModInverse(test.x.Denom(), m)
+			u.Mul(u, test.x.Num())
+			u.Mod(u, m)
+			x, ok := rationalReconstruction(u, m)
+			if ok != test.ok {
+				t.Fatalf("got %v want %v", ok, test.ok)
+			}
+			if ok && x.Cmp(test.x) != 0 {
+				t.Errorf("got %v want %v", x, test.x)
+			}
+		})
+	}
+}
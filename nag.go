@@ -9,6 +9,9 @@ package nag
 import (
 	"bytes"
 	"cmp"
+	"container/heap"
+	"context"
+	"encoding/binary"
 	"fmt"
 	"iter"
 	"math"
@@ -16,8 +19,11 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
+	"unsafe"
 
 	"github.com/jba/omap"
+	"github.com/pkg/errors"
 )
 
 // A Group is an element satisfying the [group] axioms.
@@ -83,6 +89,22 @@ func (z *Rat) Div(x, y *Rat) *Rat { return &Rat{z.Rat.Quo(x.Rat, y.Rat)} }
 // Inv sets z to 1/x and returns z. If x == 0, Inv panics.
 func (z *Rat) Inv(x *Rat) *Rat { return &Rat{z.Rat.Inv(x.Rat)} }
 
+// GCD sets z to the greatest common divisor of x and y and returns z.
+// The greatest common divisor of rationals is the largest positive rational z, such that both x/z and y/z are integers.
+// If x == y == 0, GCD sets z to 0.
+func (z *Rat) GCD(x, y *Rat) *Rat {
+	num := new(big.Int).GCD(nil, nil, new(big.Int).Abs(x.Num()), new(big.Int).Abs(y.Num()))
+	if num.Sign() == 0 {
+		z.Rat.SetInt64(0)
+		return z
+	}
+	// The denominator is the least common multiple of the denominators of x and y.
+	denom := new(big.Int).GCD(nil, nil, x.Denom(), y.Denom())
+	denom.Mul(new(big.Int).Quo(x.Denom(), denom), y.Denom())
+	z.Rat.SetFrac(num, denom)
+	return z
+}
+
 // Equal reports whether x and y are equal.
 func (x *Rat) Equal(y *Rat) bool {
 	return x.Rat.Cmp(y.Rat) == 0
@@ -94,8 +116,8 @@ func (x *Rat) String() string {
 }
 
 // A Symbol is a variable in a monomial.
-// This definition implies that the maximum number of variables supported by this package is 256.
-type Symbol = byte
+// Monomials are internally keyed by a varint encoding of their symbols, so that keys over alphabets of at most 128 symbols remain one byte per symbol.
+type Symbol = uint32
 
 // A [Monomial] is a product of variables chained by multiplication.
 //
@@ -119,12 +141,17 @@ func Deglex(x, y Monomial) int {
 }
 
 // ElimOrder returns a monomial order that first compares monomials as commutative words lexicographically, and in case of a tie applies noncommutative lexicographic order.
+// The returned order is safe for concurrent use.
 func ElimOrder() Order {
-	var xb, yb Monomial
+	// Buffers are pooled, since an order may be called from multiple goroutines, see [Options.Workers].
+	pool := sync.Pool{New: func() any { return &[2]Monomial{} }}
 	order := func(x, y Monomial) int {
-		xb, yb = xb[:0], yb[:0]
+		bufs := pool.Get().(*[2]Monomial)
+		defer pool.Put(bufs)
+		xb, yb := bufs[0][:0], bufs[1][:0]
 		xb = append(xb, x...)
 		yb = append(yb, y...)
+		bufs[0], bufs[1] = xb, yb
 
 		// Compare as commutative monomials.
 		slices.SortFunc(xb, func(a, b Symbol) int { return -cmp.Compare(a, b) })
@@ -151,7 +178,8 @@ type Polynomial[K Field[K]] struct {
 
 	field K
 	order Order
-	m     *omap.MapFunc[Monomial, K]
+	// m maps the IDs of interned monomials to coefficients, see [intern].
+	m *omap.MapFunc[wordID, K]
 }
 
 // NewPolynomial returns a new polynomial containing the given terms.
@@ -160,7 +188,7 @@ func NewPolynomial[K Field[K]](field K, order Order, terms ...PolynomialTerm[K])
 		SymbolStringer: englishSymbolStringer,
 		field:          field,
 		order:          order,
-		m:              omap.NewMapFunc[Monomial, K](order),
+		m:              omap.NewMapFunc[wordID, K](compareWords(order)),
 	}
 	for _, term := range terms {
 		x.addTerm(1, term)
@@ -178,10 +206,12 @@ func (x *Polynomial[K]) Order() Order { return x.order }
 func (x *Polynomial[K]) Len() int { return x.m.Len() }
 
 // Terms iterates the terms in a polynomial.
+// The monomials are shared with other polynomials, and must not be modified.
+// They have no spare capacity, so that appending to them allocates new monomials.
 func (x *Polynomial[K]) Terms() iter.Seq2[K, Monomial] {
 	return func(yield func(K, Monomial) bool) {
-		for w, c := range x.m.Backward() {
-			if !yield(c, w) {
+		for id, c := range x.m.Backward() {
+			if !yield(c, id.monomial()) {
 				return
 			}
 		}
@@ -196,7 +226,7 @@ func (x *Polynomial[K]) Equal(y *Polynomial[K]) bool {
 	for i := range x.m.Len() {
 		xw, xc := x.m.At(x.m.Len() - 1 - i)
 		yw, yc := y.m.At(y.m.Len() - 1 - i)
-		if !monomialEq(xw, yw) {
+		if xw != yw {
 			return false
 		}
 		if !xc.Equal(yc) {
@@ -214,11 +244,9 @@ func (z *Polynomial[K]) Set(x *Polynomial[K]) *Polynomial[K] {
 	z.SymbolStringer = x.SymbolStringer
 	z.field = x.field
 	z.order = x.order
-	z.m = omap.NewMapFunc[Monomial, K](z.order)
+	z.m = omap.NewMapFunc[wordID, K](compareWords(z.order))
 	for xw, xc := range x.m.All() {
-		w := make(Monomial, len(xw))
-		copy(w, xw)
-		z.addTerm(1, PolynomialTerm[K]{Coefficient: xc, Monomial: w})
+		z.addInterned(1, xc, xw)
 	}
 	return z
 }
@@ -232,17 +260,13 @@ func (z *Polynomial[K]) Add(x, y *Polynomial[K]) *Polynomial[K] {
 	if z != x {
 		z.m.Clear()
 		for xw, c := range x.m.All() {
-			w := make(Monomial, len(xw))
-			copy(w, xw)
-			z.addTerm(1, PolynomialTerm[K]{Coefficient: c, Monomial: w})
+			z.addInterned(1, c, xw)
 		}
 	}
 
 	// Compute z += y.
 	for yw, c := range y.m.All() {
-		w := make(Monomial, len(yw))
-		copy(w, yw)
-		z.addTerm(1, PolynomialTerm[K]{Coefficient: c, Monomial: w})
+		z.addInterned(1, c, yw)
 	}
 
 	return z
@@ -258,12 +282,12 @@ func (z *Polynomial[K]) Mul(x, y *Polynomial[K]) *Polynomial[K] {
 	}
 
 	z.m.Clear()
+	var w Monomial
 	for xw, xc := range x.m.Backward() {
 		for yw, yc := range y.m.Backward() {
 			c := z.field.Mul(xc, yc)
-			w := make(Monomial, 0, len(xw)+len(yw))
-			w = append(append(w, xw...), yw...)
-			z.addTerm(1, PolynomialTerm[K]{Coefficient: c, Monomial: w})
+			w = append(append(w[:0], xw.monomial()...), yw.monomial()...)
+			z.addMonomial(1, c, w)
 		}
 	}
 
@@ -292,13 +316,15 @@ func (z *Polynomial[K]) Pow(x *Polynomial[K], y int) *Polynomial[K] {
 
 // LeadingTerm returns the polynomial term of the leading monomial.
 // Note that the leading term depends on the monomial order employed by the polynomial.
+// The monomial is shared with other polynomials, and must not be modified.
+// It has no spare capacity, so that appending to it allocates a new monomial.
 func (x *Polynomial[K]) LeadingTerm() PolynomialTerm[K] {
 	w, ok := x.m.Max()
 	if !ok {
 		panic("zero polynomial has no terms")
 	}
 	c, _ := x.m.Get(w)
-	return PolynomialTerm[K]{Coefficient: c, Monomial: w}
+	return PolynomialTerm[K]{Coefficient: c, Monomial: w.monomial()}
 }
 
 // String returns the string representation of x.
@@ -309,7 +335,8 @@ func (x *Polynomial[K]) String() string {
 	}
 	var b strings.Builder
 	for i := range x.m.Len() {
-		w, c := x.m.At(x.m.Len() - 1 - i)
+		id, c := x.m.At(x.m.Len() - 1 - i)
+		w := id.monomial()
 
 		// Print c.
 		s := c.String()
@@ -335,17 +362,32 @@ func (x *Polynomial[K]) String() string {
 }
 
 func (x *Polynomial[K]) addTerm(sign int, term PolynomialTerm[K]) {
-	c, ok := x.m.Get(term.Monomial)
-	if !ok {
-		c = x.field.NewZero()
-	}
-
 	tc := term.Coefficient
 	tcv := reflect.ValueOf(tc)
 	kind := tcv.Kind()
 	if (kind == reflect.Pointer || kind == reflect.Interface) && tcv.IsNil() {
 		tc = x.field.NewOne()
 	}
+	x.addMonomial(sign, tc, term.Monomial)
+}
+
+// addMonomial adds sign*tc*w to x.
+// The monomial w is interned, so that w may be a reused buffer.
+func (x *Polynomial[K]) addMonomial(sign int, tc K, w Monomial) {
+	x.addInterned(sign, tc, intern(w))
+}
+
+// addInterned adds sign*tc*w to x, where w is the ID of an interned monomial.
+func (x *Polynomial[K]) addInterned(sign int, tc K, w wordID) {
+	c, ok := x.m.Get(w)
+	if !ok {
+		c = x.field.NewZero()
+	}
+	x.setCoefficient(sign, tc, w, c)
+}
+
+// setCoefficient sets the coefficient of w in x to c+sign*tc.
+func (x *Polynomial[K]) setCoefficient(sign int, tc K, w wordID, c K) {
 	if sign < 0 {
 		c.Sub(c, tc)
 	} else {
@@ -353,18 +395,18 @@ func (x *Polynomial[K]) addTerm(sign int, term PolynomialTerm[K]) {
 	}
 
 	if c.Equal(x.field.NewZero()) {
-		x.m.Delete(term.Monomial)
+		x.m.Delete(w)
 	} else {
-		x.m.Set(term.Monomial, c)
+		x.m.Set(w, c)
 	}
 }
 
 func (z *Polynomial[K]) add(sign int, c K, left Monomial, x *Polynomial[K], right Monomial) {
+	var w Monomial
 	for xw, xc := range x.m.Backward() {
 		c := z.field.Mul(c, xc)
-		w := make(Monomial, 0, len(left)+len(xw)+len(right))
-		w = append(append(append(w, left...), xw...), right...)
-		z.addTerm(sign, PolynomialTerm[K]{Coefficient: c, Monomial: w})
+		w = append(append(append(w[:0], left...), xw.monomial()...), right...)
+		z.addMonomial(sign, c, w)
 	}
 }
 
@@ -379,10 +421,7 @@ func (z *Polynomial[K]) mulScalar(scalar K, x *Polynomial[K]) *Polynomial[K] {
 
 	z.m.Clear()
 	for xw, xc := range x.m.All() {
-		c := z.field.Mul(scalar, xc)
-		w := make(Monomial, len(xw))
-		copy(w, xw)
-		z.addTerm(1, PolynomialTerm[K]{Coefficient: c, Monomial: w})
+		z.addInterned(1, z.field.Mul(scalar, xc), xw)
 	}
 	return z
 }
@@ -406,145 +445,486 @@ type Quotient[K Field[K]] struct {
 
 // Divide divides the polynomial f by the ideal g, and returns the quotient and remainder.
 // The polynomial f is modified upon return.
+// Each call builds a [Reducer] for g, including its Aho-Corasick automaton over the leading monomials of g.
+// To divide many polynomials by the same ideal, build a [Reducer] once instead, or use [Reducer.NormalForm] which additionally caches the normal forms of monomials.
 // For more details, please see Theorem 3.2.1, Xiu Xingqiang.
 //
 // Xiu, Xingqiang. "Non-commutative Gröbner bases and applications." PhD diss., Universität Passau, 2012.
 func Divide[K Field[K]](quotient [][]Quotient[K], f *Polynomial[K], g []*Polynomial[K]) (outQuotient [][]Quotient[K], remainder *Polynomial[K]) {
-	if quotient != nil {
-		short := len(g) - len(quotient)
-		if short > 0 {
-			quotient = append(quotient, make([][]Quotient[K], short)...)
-		}
-		quotient = quotient[:len(g)]
-		for i := range quotient {
-			quotient[i] = quotient[i][:0]
+	outQuotient, remainder, _ = divide(nil, quotient, f, g)
+	return outQuotient, remainder
+}
+
+// DivideContext is like [Divide], but stops early with the context's error when ctx is done.
+// Upon cancellation, the returned remainder is incomplete and should not be used.
+func DivideContext[K Field[K]](ctx context.Context, quotient [][]Quotient[K], f *Polynomial[K], g []*Polynomial[K]) (outQuotient [][]Quotient[K], remainder *Polynomial[K], err error) {
+	outQuotient, remainder, err = divide(ctx.Done(), quotient, f, g)
+	if err != nil {
+		return outQuotient, remainder, errors.Wrap(ctx.Err(), "")
+	}
+	return outQuotient, remainder, nil
+}
+
+// IsMember reports whether f belongs to the ideal generated by basis, together with the quotient of f divided by basis.
+// If f is a member, the quotient expresses f in terms of basis, in the same way as [Divide]:
+//
+//	f = basis*quotient
+//
+// Membership is decided by checking that the remainder of f divided by basis is zero.
+// A true result is always correct, whereas a false result is conclusive only if basis is a complete Gröbner basis.
+// Like [Divide], each call builds a [Reducer] for basis.
+// Unlike [Divide], f is not modified.
+func IsMember[K Field[K]](f *Polynomial[K], basis []*Polynomial[K]) (member bool, quotient [][]Quotient[K]) {
+	f = NewPolynomial(f.field.NewZero(), f.order).Set(f)
+	quotient, remainder := Divide([][]Quotient[K]{}, f, basis)
+	return remainder.m.Len() == 0, quotient
+}
+
+// divide implements [Divide], and checks before each reduction step whether done is closed.
+// A nil done never closes.
+func divide[K Field[K]](done <-chan struct{}, quotient [][]Quotient[K], f *Polynomial[K], g []*Polynomial[K]) ([][]Quotient[K], *Polynomial[K], error) {
+	return NewReducer(g).divide(done, quotient, f, -1)
+}
+
+// Buchberger returns the Gröbner basis of the ideal g, using the Buchberger algorithm.
+// For noncummutative algebras, a Gröbner basis may not be finite and therefore only a partial basis can be computed.
+// In this case, upon reaching maxIter without a complete basis, Buchberger sets complete to false.
+// For more details, please see Theorem 4.2.24, Xiu Xingqiang.
+//
+// Xiu, Xingqiang. "Non-commutative Gröbner bases and applications." PhD diss., Universität Passau, 2012.
+func Buchberger[K Field[K]](g []*Polynomial[K], maxIter int) (basis []*Polynomial[K], complete bool) {
+	basis, complete, _ = BuchbergerContext(context.Background(), g, maxIter)
+	return basis, complete
+}
+
+// BuchbergerContext is like [Buchberger], but stops early when ctx is done.
+// The context is checked between obstructions as well as during polynomial division.
+// Upon cancellation, BuchbergerContext returns the partial basis computed so far together with the context's error.
+// The partial basis is monic and sorted, but unlike a finished run it is not interreduced.
+func BuchbergerContext[K Field[K]](ctx context.Context, g []*Polynomial[K], maxIter int) (basis []*Polynomial[K], complete bool, err error) {
+	basis, complete, _, err = BuchbergerWithOptions(ctx, g, maxIter, nil)
+	return basis, complete, err
+}
+
+// BuchbergerWithOptions is like [BuchbergerContext], but computes the basis as configured by opts, and reports statistics of the computation.
+// A nil opts is equivalent to a zero [Options].
+func BuchbergerWithOptions[K Field[K]](ctx context.Context, g []*Polynomial[K], maxIter int, opts *Options) (basis []*Polynomial[K], complete bool, stats Stats, err error) {
+	c, err := newCheckpoint(ctx.Done(), g, false)
+	if err != nil {
+		basis = c.basis(false)
+		return basis, false, Stats{Basis: len(basis)}, errors.Wrap(ctx.Err(), "")
+	}
+	return c.Resume(ctx, maxIter, opts)
+}
+
+// A Checkpoint is the state of a [Buchberger] computation.
+// A computation can be continued from a checkpoint by calling [Checkpoint.Resume] with a larger maxIter.
+// Checkpoints can also be saved with [Checkpoint.WriteTo] and restored with [ReadCheckpoint], which allows a long computation to span multiple processes.
+type Checkpoint[K Field[K]] struct {
+	// g is the current basis.
+	g []*Polynomial[K]
+	// t tracks unwanted polynomials in g.
+	t []*Polynomial[K]
+	// sugar are the sugar degrees of the polynomials in g.
+	sugar []int
+	// b is the set of obstructions.
+	b obstructionQueue[K]
+	// seq is the number of obstructions created so far.
+	seq int
+	// iter is the number of obstructions processed so far.
+	iter int
+	// complete reports whether all obstructions have been processed.
+	complete bool
+	stats    Stats
+	// reducer divides by the wanted polynomials in g, and is rebuilt after g changes.
+	reducer *Reducer[K]
+	// backend is the backend of reducer.
+	backend Backend
+	// cofactors express the polynomials in g in terms of the input ideal.
+	// It is nil unless the computation is started by [BuchbergerCofactors].
+	cofactors []cofactorSum[K]
+}
+
+// NewCheckpoint returns a checkpoint at the start of a [Buchberger] computation of the ideal g.
+func NewCheckpoint[K Field[K]](g []*Polynomial[K]) *Checkpoint[K] {
+	c, _ := newCheckpoint(nil, g, false)
+	return c
+}
+
+// newCheckpoint returns a checkpoint at the start of a computation of the ideal g, which also tracks cofactors if cofactors is true.
+func newCheckpoint[K Field[K]](done <-chan struct{}, g []*Polynomial[K], cofactors bool) (*Checkpoint[K], error) {
+	// Make a copy of g since interreduction modifies it.
+	g = slices.Clone(g)
+	var cfs []cofactorSum[K]
+	if cofactors {
+		cfs = make([]cofactorSum[K], len(g))
+		for i := range g {
+			cfs[i] = newCofactorSum(i, g[0].field)
 		}
 	}
-	p := NewPolynomial[K](f.field, f.order)
-	p.SymbolStringer = f.SymbolStringer
-	v := f
+	g, cfs, err := interreduceDone(done, g, cfs)
+	c := &Checkpoint[K]{g: g, t: make([]*Polynomial[K], len(g)), sugar: make([]int, len(g)), cofactors: cfs}
+	c.b.order = g[0].order
+	for i, gi := range g {
+		c.sugar[i] = degree(gi)
+	}
+	if err != nil {
+		return c, err
+	}
 
-	for v.m.Len() != 0 {
-		lmv := v.LeadingTerm()
-		ltv := lmv.Monomial
+	buf := &Monomial{}
+	for l := 1; l <= len(g); l++ {
+		c.pushObstructions(g[:l], buf)
+	}
+	c.stats.Obstructions = c.b.Len()
+	c.stats.Basis = len(c.g)
+	return c, nil
+}
 
-		// Find basis where ltv = left * ltg * right.
-		basis, leftEnd, lmg := -1, -1, PolynomialTerm[K]{}
-		for i, gi := range g {
-			if gi == nil {
-				continue
+// Resume continues the computation until a total of maxIter obstructions have been processed since the start of the computation, and returns the basis the same way as [BuchbergerWithOptions].
+// Upon cancellation, c remains valid and can be resumed again.
+func (c *Checkpoint[K]) Resume(ctx context.Context, maxIter int, opts *Options) (basis []*Polynomial[K], complete bool, stats Stats, err error) {
+	basis, _, complete, stats, err = c.resume(ctx, maxIter, opts)
+	return basis, complete, stats, err
+}
+
+// resume implements Resume, and also returns the cofactors of the basis if c tracks them.
+func (c *Checkpoint[K]) resume(ctx context.Context, maxIter int, opts *Options) (basis []*Polynomial[K], cofactors [][]Cofactor[K], complete bool, stats Stats, err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	done := ctx.Done()
+	// Buffers.
+	r0 := c.g[0].field.NewZero()
+	buf := &Monomial{}
+
+	var gcd func(x, y K) K
+	if opts.FractionFree {
+		gcd = gcdFunc(r0)
+	}
+	if gcd != nil {
+		c.primitive(gcd)
+	}
+
+	if opts.Backend != c.backend {
+		c.backend, c.reducer = opts.Backend, nil
+	}
+
+	// Order the obstructions by the requested strategy.
+	c.b.strategy = opts.Strategy
+	heap.Init(&c.b)
+	// deferred are obstructions whose sugar degrees exceed opts.MaxSugar.
+	// They are put back into b upon return, so that c can be resumed with a larger MaxSugar.
+	var deferred []obstruction[K]
+
+	for c.iter < maxIter {
+		// Select a batch of obstructions using the strategy.
+		// popped are the obstructions in the order they are popped, and batch are those among them that are not deferred, at positions pos in popped.
+		n := min(max(opts.Workers, 1), maxIter-c.iter)
+		var popped []obstruction[K]
+		batch := make([]obstruction[K], 0, n)
+		pos := make([]int, 0, n)
+		for len(batch) < n && c.b.Len() > 0 {
+			o := heap.Pop(&c.b).(obstruction[K])
+			if opts.MaxSugar <= 0 || o.sugar <= opts.MaxSugar {
+				batch, pos = append(batch, o), append(pos, len(popped))
 			}
-			lmg = gi.LeadingTerm()
-			ltg := lmg.Monomial
+			popped = append(popped, o)
+		}
+		if len(batch) == 0 {
+			deferred = append(deferred, popped...)
+			c.complete = len(deferred) == 0
+			break
+		}
 
-			if leftEnd = monomialIndex(ltv, ltg); leftEnd != -1 {
-				basis = i
+		// Reduce the S-polynomials of the batch.
+		reductions, divErr := c.reduceBatch(done, batch, r0, gcd)
+		if divErr != nil {
+			// Put the batch back so that c remains valid.
+			deferred = append(deferred, popped...)
+			err = errors.Wrap(ctx.Err(), "")
+			break
+		}
+
+		// Commit the remainders in the order of their obstructions.
+		// Remainders in a batch are computed against the same basis, and are thus the same as those computed one at a time, until one of them is added to the basis.
+		// next is the position in popped of the first obstruction not yet committed.
+		var next int
+		for k, red := range reductions {
+			// Stop at the same reduction as reducing one at a time, which checks done before each reduction.
+			select {
+			case <-done:
+				divErr = errCanceled
+			default:
+			}
+			if divErr != nil {
 				break
 			}
-		}
+			deferred = append(deferred, popped[next:pos[k]]...)
+			next = pos[k] + 1
+			sP := red.remainder
+			if sP.m.Len() != 0 {
+				// Adding sP changes the remainders of the rest of the batch, and may remove their obstructions by the criteria.
+				// Put them back before adding sP, so that results are the same as reducing one at a time.
+				for _, o := range popped[next:] {
+					heap.Push(&c.b, o)
+				}
+				next = len(popped)
+			}
+			var cf cofactorSum[K]
+			if c.cofactors != nil && sP.m.Len() != 0 {
+				cf = sPolynomialCofactors(batch[k], c.g, c.cofactors, gcd)
+				cf.scale(red.scale)
+				cf = reduceCofactors(cf, red.quotient, c.cofactors)
+			}
 
-		if basis == -1 {
-			p.addTerm(1, lmv)
-			v.addTerm(-1, lmv)
-		} else {
-			q := Quotient[K]{
-				Coefficient: f.field.NewZero().Div(lmv.Coefficient, lmg.Coefficient),
-				Left:        ltv[:leftEnd],
-				Right:       ltv[leftEnd+len(lmg.Monomial):],
+			c.iter++
+			c.stats.Reductions++
+			c.stats.Sugar = batch[k].sugar
+			if sP.m.Len() != 0 {
+				c.add(sP, red.sugar, cf, buf)
+			} else {
+				c.stats.ZeroReductions++
+			}
+
+			c.stats.Obstructions = c.b.Len() + len(deferred) + len(popped) - next
+			if opts.Observer != nil {
+				opts.Observer(c.stats)
 			}
-			if quotient != nil {
-				quotient[basis] = append(quotient[basis], q)
+			if next == len(popped) {
+				break
 			}
-			v.add(-1, q.Coefficient, q.Left, g[basis], q.Right)
+		}
+		deferred = append(deferred, popped[next:]...)
+		if divErr != nil {
+			err = errors.Wrap(ctx.Err(), "")
+			break
+		}
+	}
+	for _, o := range deferred {
+		heap.Push(&c.b, o)
+	}
+	c.stats.Obstructions = c.b.Len()
+	if err == nil {
+		select {
+		case <-done:
+			err = errors.Wrap(ctx.Err(), "")
+		default:
 		}
 	}
 
-	return quotient, p
+	basis, cofactors = c.basisCofactors(err == nil)
+	stats = c.stats
+	stats.Basis = len(basis)
+	if err != nil {
+		return basis, cofactors, false, stats, err
+	}
+	return basis, cofactors, c.complete, stats, nil
 }
 
-// Buchberger returns the Gröbner basis of the ideal g, using the Buchberger algorithm.
-// For noncummutative algebras, a Gröbner basis may not be finite and therefore only a partial basis can be computed.
-// In this case, upon reaching maxIter without a complete basis, Buchberger sets complete to false.
-// For more details, please see Theorem 4.2.24, Xiu Xingqiang.
-//
-// Xiu, Xingqiang. "Non-commutative Gröbner bases and applications." PhD diss., Universität Passau, 2012.
-func Buchberger[K Field[K]](g []*Polynomial[K], maxIter int) (basis []*Polynomial[K], complete bool) {
-	// Buffers.
-	r0 := g[0].field.NewZero()
-	buf := &Monomial{}
+// primitive divides the polynomials in the basis by their contents, so that fraction-free reduction starts from primitive polynomials.
+// Polynomials are replaced instead of modified, since they may be shared with the input ideal.
+func (c *Checkpoint[K]) primitive(gcd func(x, y K) K) {
+	for i, gi := range c.g {
+		content := gi.field.NewZero()
+		for coeff := range gi.Terms() {
+			content = gcd(content, coeff)
+		}
+		if content.Equal(gi.field.NewOne()) {
+			continue
+		}
 
-	g = interreduce(g)
-	// t tracks unwanted polynomials in g.
-	t := make([]*Polynomial[K], len(g))
-	deleteUnwanted := func() {
-		for i := range t {
-			if t[i] != nil {
-				g[i] = nil
-			}
+		inv := gi.field.NewZero().Inv(content)
+		p := NewPolynomial(gi.field.NewZero(), gi.order)
+		p.SymbolStringer = gi.SymbolStringer
+		p.mulScalar(inv, gi)
+		if c.t[i] != nil {
+			c.t[i] = p
 		}
+		c.g[i] = p
+		if c.cofactors != nil {
+			c.cofactors[i].scale(inv)
+		}
+		c.reducer = nil
 	}
-	restoreUnwanted := func() {
-		for i := range t {
-			if t[i] != nil {
-				g[i] = t[i]
+}
+
+// reduceBatch returns the reductions of the S-polynomials of obs by the basis.
+// If obs has more than one obstruction, the S-polynomials are reduced concurrently, each in its own goroutine.
+// If gcd is not nil, the S-polynomials are reduced fraction-free.
+func (c *Checkpoint[K]) reduceBatch(done <-chan struct{}, obs []obstruction[K], r0 K, gcd func(x, y K) K) ([]reduction[K], error) {
+	reducer := c.wantedReducer()
+	if len(obs) == 1 {
+		s := sPolynomial[K](obs[0], c.g, r0, gcd)
+		red, err := reduce(done, s, obs[0].sugar, reducer, c.sugar, gcd)
+		return []reduction[K]{red}, err
+	}
+
+	reductions := make([]reduction[K], len(obs))
+	errs := make([]error, len(obs))
+	var wg sync.WaitGroup
+	for i, o := range obs {
+		wg.Go(func() {
+			s := sPolynomial[K](o, c.g, r0.NewZero(), gcd)
+			reductions[i], errs[i] = reduce(done, s, o.sugar, reducer, c.sugar, gcd)
+		})
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return reductions, nil
+}
+
+// A reduction is the result of dividing a polynomial f by a basis g:
+//
+//	remainder = scale*f - g*quotient
+type reduction[K Field[K]] struct {
+	remainder *Polynomial[K]
+	// sugar is the sugar degree of the remainder.
+	sugar    int
+	quotient [][]Quotient[K]
+	// scale is one, unless the division is fraction-free.
+	scale K
+}
+
+// reduce divides f by the basis of r.
+// The sugar degree of the remainder is the largest among the sugar degree of f, and those of the multiples of the basis subtracted from f.
+// If gcd is not nil, f is divided fraction-free, and the remainder is primitive.
+func reduce[K Field[K]](done <-chan struct{}, f *Polynomial[K], sugar int, r *Reducer[K], gSugar []int, gcd func(x, y K) K) (reduction[K], error) {
+	red := reduction[K]{scale: f.field.NewOne()}
+	var err error
+	if gcd == nil {
+		red.quotient, red.remainder, err = r.divide(done, [][]Quotient[K]{}, f, -1)
+	} else {
+		fContent := primitive(f, gcd)
+		red.quotient, red.remainder, red.scale, err = r.divideFractionFree(done, [][]Quotient[K]{}, f, -1, gcd)
+		content := primitive(red.remainder, gcd)
+
+		// Divide the scale and quotient by the contents, so that the remainder remains the same combination of the original f and g.
+		red.scale = red.scale.Div(red.scale, f.field.NewZero().Mul(fContent, content))
+		for i := range red.quotient {
+			for j := range red.quotient[i] {
+				q := &red.quotient[i][j]
+				q.Coefficient = q.Coefficient.Div(q.Coefficient, content)
 			}
 		}
 	}
-	// b is the set of obstructions.
-	var b []obstruction[K]
-	for l := 1; l <= len(g); l++ {
-		gl := g[:l]
-		b = addObstructions(b, gl, buf)
+
+	red.sugar = sugar
+	for i := range red.quotient {
+		for _, q := range red.quotient[i] {
+			red.sugar = max(red.sugar, len(q.Left)+gSugar[i]+len(q.Right))
+		}
 	}
+	return red, err
+}
 
-	for range maxIter {
-		if len(b) == 0 {
-			complete = true
-			break
+// add adds the polynomial sP with the given sugar degree and cofactors to the basis.
+func (c *Checkpoint[K]) add(sP *Polynomial[K], sugar int, cofactors cofactorSum[K], buf *Monomial) {
+	// Add sP to g and add new obstructions.
+	c.g = append(c.g, sP)
+	c.t = append(c.t, nil)
+	c.sugar = append(c.sugar, sugar)
+	c.reducer = nil
+	if c.cofactors != nil {
+		c.cofactors = append(c.cofactors, cofactors)
+	}
+	c.pushObstructions(c.g, buf)
+	c.stats.Basis++
+
+	// Set gi as unwanted if ltgi is a multiple of ltgs.
+	ltgs := sP.LeadingTerm().Monomial
+	for i := range len(c.g) - 1 {
+		ltgi := c.g[i].LeadingTerm().Monomial
+		if c.t[i] == nil && monomialIndex(ltgi, ltgs) != -1 {
+			c.t[i] = c.g[i]
+			c.stats.Basis--
 		}
+	}
+}
 
-		// Take oij from b.
-		oij := b[0]
-		for i := range len(b) - 1 {
-			b[i] = b[i+1]
+// pushObstructions adds the obstructions of the last polynomial in g to b.
+func (c *Checkpoint[K]) pushObstructions(g []*Polynomial[K], buf *Monomial) {
+	c.b.s = addObstructions(c.b.s, g, buf, &c.stats)
+	for i := range c.b.s {
+		if c.b.s[i].seq == 0 {
+			c.seq++
+			c.b.s[i].seq = c.seq
+			c.setPriority(&c.b.s[i])
 		}
-		b = b[:len(b)-1]
+	}
+	// Restore the heap, since addObstructions appends to and deletes from b.
+	heap.Init(&c.b)
+}
 
-		// Compute S-polynomial of oij.
-		s := sPolynomial[K](oij, g, r0)
-		deleteUnwanted()
-		_, sP := Divide(nil, s, g)
-		restoreUnwanted()
-		if sP.m.Len() == 0 {
-			continue
+// setPriority sets the fields of o that are used by selection strategies.
+func (c *Checkpoint[K]) setPriority(o *obstruction[K]) {
+	o.word = slices.Concat(o.iLeft, c.g[o.i].LeadingTerm().Monomial, o.iRight)
+	o.sugar = max(len(o.iLeft)+c.sugar[o.i]+len(o.iRight), len(o.jLeft)+c.sugar[o.j]+len(o.jRight))
+}
+
+// wantedReducer returns a reducer for the wanted polynomials in g.
+// Unwanted polynomials are replaced by nil, so that indices of the reducer are the same as those of g.
+func (c *Checkpoint[K]) wantedReducer() *Reducer[K] {
+	if c.reducer != nil {
+		return c.reducer
+	}
+	g := slices.Clone(c.g)
+	for i := range c.t {
+		if c.t[i] != nil {
+			g[i] = nil
 		}
+	}
+	c.reducer = NewReducerBackend(g, c.backend)
+	return c.reducer
+}
 
-		// Add sP to g and add new obstructions.
-		g = append(g, sP)
-		t = append(t, nil)
-		b = addObstructions(b, g, buf)
+// basis returns a monic and sorted copy of the wanted polynomials in c, which are interreduced if reduce is true.
+func (c *Checkpoint[K]) basis(reduce bool) []*Polynomial[K] {
+	g, _ := c.basisCofactors(reduce)
+	return g
+}
 
-		// Set gi as unwanted if ltgi is a multiple of ltgs.
-		ltgs := g[len(g)-1].LeadingTerm().Monomial
-		for i := range len(g) - 1 {
-			ltgi := g[i].LeadingTerm().Monomial
-			if monomialIndex(ltgi, ltgs) != -1 {
-				t[i] = g[i]
-			}
+// basisCofactors is like basis, but also returns the cofactors of the basis if c tracks them.
+func (c *Checkpoint[K]) basisCofactors(reduce bool) ([]*Polynomial[K], [][]Cofactor[K]) {
+	g := make([]*Polynomial[K], 0, len(c.g))
+	var cfs []cofactorSum[K]
+	for i, gi := range c.g {
+		if c.t[i] != nil {
+			continue
+		}
+		g = append(g, NewPolynomial(gi.field, gi.order).Set(gi))
+		if c.cofactors != nil {
+			cf := make(cofactorSum[K], len(c.cofactors[i]))
+			cf.add(1, gi.field.NewOne(), nil, c.cofactors[i], nil)
+			cfs = append(cfs, cf)
 		}
 	}
+	if reduce {
+		g, cfs, _ = interreduceDone(nil, g, cfs)
+	}
+	return monicSortedCofactors(g, cfs)
+}
 
-	// Remove unwanted polynomials.
-	deleteUnwanted()
-	g = slices.DeleteFunc(g, func(gi *Polynomial[K]) bool { return gi == nil })
-	g = interreduce(g)
-	// Make basis monic.
-	for i := range g {
-		lc := g[i].LeadingTerm().Coefficient
-		g[i].mulScalar(r0.Inv(lc), g[i])
+// isGroebner reports whether g is a Gröbner basis, by checking that all S-polynomials of obstructions reduce to zero.
+func isGroebner[K Field[K]](g []*Polynomial[K]) bool {
+	g = slices.Clone(g)
+	for i, f := range g {
+		g[i] = NewPolynomial(f.field.NewZero(), f.order).Set(f)
+	}
+	c, _ := newCheckpoint(nil, g, false)
+	r0 := c.g[0].field.NewZero()
+	reducer := NewReducer(c.g)
+	for _, o := range c.b.s {
+		sP := sPolynomial(o, c.g, r0, nil)
+		if _, r := reducer.Divide(nil, sP); r.Len() != 0 {
+			return false
+		}
 	}
-	slices.SortFunc(g, polynomialCmp[K])
-	return g, complete
+	return true
 }
 
 // BuchbergerHomogeneous returns the Gröbner basis of the [homogeneous] ideal g, using the Buchberger algorithm.
@@ -555,13 +935,33 @@ func Buchberger[K Field[K]](g []*Polynomial[K], maxIter int) (basis []*Polynomia
 //
 // [homogeneous]: https://en.wikipedia.org/wiki/Homogeneous_polynomial
 func BuchbergerHomogeneous[K Field[K]](g []*Polynomial[K], maxDeg int) (basis []*Polynomial[K], complete bool) {
+	basis, complete, _ = BuchbergerHomogeneousContext(context.Background(), g, maxDeg)
+	return basis, complete
+}
+
+// BuchbergerHomogeneousContext is like [BuchbergerHomogeneous], but stops early when ctx is done.
+// The context is checked between obstructions as well as during polynomial division.
+// Upon cancellation, BuchbergerHomogeneousContext returns the partial basis computed so far together with the context's error.
+// The partial basis is monic and sorted, but unlike a finished run it is not interreduced.
+func BuchbergerHomogeneousContext[K Field[K]](ctx context.Context, g []*Polynomial[K], maxDeg int) (basis []*Polynomial[K], complete bool, err error) {
+	basis, complete, _, err = BuchbergerHomogeneousWithOptions(ctx, g, maxDeg, nil)
+	return basis, complete, err
+}
+
+// BuchbergerHomogeneousWithOptions is like [BuchbergerHomogeneousContext], but computes the basis as configured by opts, and reports statistics of the computation.
+// A nil opts is equivalent to a zero [Options].
+func BuchbergerHomogeneousWithOptions[K Field[K]](ctx context.Context, g []*Polynomial[K], maxDeg int, opts *Options) (basis []*Polynomial[K], complete bool, stats Stats, err error) {
 	// Check that g is a homogeneous ideal.
 	for _, f := range g {
 		if !homogeneous(f) {
 			panic(fmt.Sprintf("non-homogeneous polynomial %v", f))
 		}
 	}
+	if opts == nil {
+		opts = &Options{}
+	}
 
+	done := ctx.Done()
 	// Buffers.
 	r0 := g[0].field.NewZero()
 	m0 := &Monomial{}
@@ -578,6 +978,49 @@ func BuchbergerHomogeneous[K Field[K]](g []*Polynomial[K], maxDeg int) (basis []
 	var bd []obstruction[K]
 	var numDeleted int
 
+	// remainders are the remainders of the current degree computed together by MatrixReduction, in the order they are processed.
+	var remainders []*Polynomial[K]
+	// reducer divides by basis, and is rebuilt after basis changes.
+	var reducer *Reducer[K]
+	// reduce reduces f by the current basis, and adds the remainder to the basis if it is non-zero.
+	reduce := func(f *Polynomial[K], isSPolynomial bool) error {
+		var fP *Polynomial[K]
+		if opts.Reduction == MatrixReduction {
+			fP, remainders = remainders[0], remainders[1:]
+		} else {
+			if reducer == nil {
+				reducer = NewReducerBackend(basis, opts.Backend)
+			}
+			var err error
+			if _, fP, err = reducer.divide(done, nil, p0.Set(f), -1); err != nil {
+				return err
+			}
+		}
+		if isSPolynomial {
+			stats.Reductions++
+		}
+		if fP.m.Len() == 0 {
+			if isSPolynomial {
+				stats.ZeroReductions++
+			}
+		} else {
+			basis = append(basis, fP)
+			reducer = nil
+			b = addObstructions(b, basis, m0, &stats)
+			var nDel int
+			b, nDel = deleteHighDegObs(b, basis, maxDeg, r0)
+			numDeleted += nDel
+		}
+
+		stats.Obstructions = len(b) + len(bd)
+		stats.Basis = len(basis)
+		if opts.Observer != nil {
+			opts.Observer(stats)
+		}
+		return nil
+	}
+
+Loop:
 	for {
 		if len(g) == 0 && len(b) == 0 {
 			if numDeleted == 0 {
@@ -590,73 +1033,255 @@ func BuchbergerHomogeneous[K Field[K]](g []*Polynomial[K], maxDeg int) (basis []
 		if !stMax {
 			break
 		}
+		if len(gd) > 0 {
+			stats.Degree = len(gd[0].LeadingTerm().Monomial)
+		} else {
+			stats.Degree = len(bd[0].sPolynomial.LeadingTerm().Monomial)
+		}
+		if opts.Reduction == MatrixReduction {
+			var targets []*Polynomial[K]
+			for _, f := range slices.Backward(gd) {
+				targets = append(targets, f)
+			}
+			for _, o := range slices.Backward(bd) {
+				targets = append(targets, o.sPolynomial)
+			}
+			var mat *macaulay[K]
+			if mat, err = newMacaulay(done, targets, basis); err != nil {
+				break Loop
+			}
+			if remainders, err = mat.reduce(done, targets); err != nil {
+				break Loop
+			}
+		}
 
 		for len(gd) > 0 {
 			gL := gd[len(gd)-1]
 			gd = gd[:len(gd)-1]
-			_, gP := Divide(nil, p0.Set(gL), basis)
-			if gP.m.Len() == 0 {
-				continue
+			if err = reduce(gL, false); err != nil {
+				break Loop
 			}
-
-			basis = append(basis, gP)
-			b = addObstructions(b, basis, m0)
-			var nDel int
-			b, nDel = deleteHighDegObs(b, basis, maxDeg, r0)
-			numDeleted += nDel
 		}
 
 		for len(bd) > 0 {
 			o := bd[len(bd)-1]
 			bd = bd[:len(bd)-1]
-			_, sP := Divide(nil, p0.Set(o.sPolynomial), basis)
-			if sP.m.Len() == 0 {
-				continue
+			if err = reduce(o.sPolynomial, true); err != nil {
+				break Loop
 			}
-
-			basis = append(basis, sP)
-			b = addObstructions(b, basis, m0)
-			var nDel int
-			b, nDel = deleteHighDegObs(b, basis, maxDeg, r0)
-			numDeleted += nDel
 		}
 	}
+	stats.Obstructions = len(b) + len(bd)
+	if err != nil {
+		stats.Basis = len(basis)
+		return monicSorted(basis), false, stats, errors.Wrap(ctx.Err(), "")
+	}
 
 	basis = interreduce(basis)
-	// Make basis monic.
-	for i := range basis {
-		lc := basis[i].LeadingTerm().Coefficient
-		basis[i].mulScalar(r0.Inv(lc), basis[i])
+	stats.Basis = len(basis)
+	return monicSorted(basis), complete, stats, nil
+}
+
+// Options are options for computing Gröbner bases.
+type Options struct {
+	// Observer, if not nil, is called with the latest statistics each time an S-polynomial or input polynomial is processed.
+	Observer func(Stats)
+	// Reduction is the method for reducing polynomials in [BuchbergerHomogeneousWithOptions].
+	Reduction Reduction
+	// Workers is the number of S-polynomials that [BuchbergerWithOptions] reduces concurrently.
+	// Each batch of Workers obstructions is reduced against the same basis, and the remainders are then committed in the order of their obstructions.
+	// Once a remainder is added to the basis, the rest of the batch is put back and reduced again against the new basis.
+	// Hence results, including partial results truncated by maxIter, are the same as those of reducing one S-polynomial at a time, and do not depend on scheduling.
+	// The reductions put back are wasted work, so batches pay off only with multiple CPUs, and when most S-polynomials reduce to zero.
+	// Values less than 2 reduce S-polynomials one at a time.
+	// When Workers is larger than 1, the monomial order of the basis is called from multiple goroutines, and must be safe for concurrent use.
+	Workers int
+	// Strategy is the rule for selecting the next obstruction in [BuchbergerWithOptions].
+	Strategy Strategy
+	// MaxSugar, if positive, bounds the sugar degrees of obstructions processed by [BuchbergerWithOptions].
+	// Obstructions with larger sugar degrees are left unprocessed, in which case the returned basis is not complete.
+	// Similar to maxDeg in [BuchbergerHomogeneous], the basis then contains all polynomials of the Gröbner basis up to sugar degree MaxSugar.
+	// For homogeneous ideals, the sugar degree of a polynomial is its degree, and the basis is the same as that of [BuchbergerHomogeneous].
+	MaxSugar int
+	// FractionFree, if true, reduces S-polynomials in [BuchbergerWithOptions] without dividing coefficients.
+	// Polynomials are kept primitive, with their content divided out, and reduction steps multiply by leading coefficients instead of dividing by them.
+	// This avoids the growth of fractions in fields such as [Rat], and the returned basis is still monic.
+	// FractionFree requires the coefficient field to have a GCD method like [Rat.GCD], and is ignored otherwise.
+	FractionFree bool
+	// Backend is the representation of the intermediate polynomials when dividing by the basis.
+	Backend Backend
+}
+
+// A gcdField is a field whose elements have greatest common divisors, see [Options.FractionFree].
+type gcdField[K any] interface {
+	// GCD sets z to the greatest common divisor of x and y and returns z, where z is the method receiver.
+	GCD(x, y K) K
+}
+
+// gcdFunc returns the greatest common divisor function of the field of r0, or nil if the field does not have one.
+func gcdFunc[K Field[K]](r0 K) func(x, y K) K {
+	if _, ok := any(r0).(gcdField[K]); !ok {
+		return nil
+	}
+	return func(x, y K) K {
+		return any(r0.NewZero()).(gcdField[K]).GCD(x, y)
 	}
-	slices.SortFunc(basis, polynomialCmp[K])
-	return basis, complete
+}
+
+// A Strategy is a rule for selecting the next obstruction to process.
+// For more details, please see Giovini et al.
+//
+// Giovini, Alessandro, et al. "“One sugar cube, please” or selection strategies in the Buchberger algorithm." Proceedings of the 1991 international symposium on Symbolic and algebraic computation. 1991.
+type Strategy int
+
+const (
+	// FIFOStrategy selects obstructions in the order they are created.
+	FIFOStrategy Strategy = iota
+	// NormalStrategy selects the obstruction whose S-polynomial has the smallest leading monomial under the monomial order, before cancellation.
+	NormalStrategy
+	// SugarStrategy selects the obstruction with the smallest sugar degree, and breaks ties with NormalStrategy.
+	// The sugar degree of a polynomial is the degree it would have had if the ideal were homogenized.
+	SugarStrategy
+)
+
+// A Reduction is a method for reducing polynomials by a basis.
+type Reduction int
+
+const (
+	// DivideReduction reduces polynomials one at a time using [Divide].
+	DivideReduction Reduction = iota
+	// MatrixReduction reduces all polynomials of the same degree together, by row reducing a sparse Macaulay matrix in the style of the F4 algorithm.
+	// The polynomials of each degree and their reducers are collected into a matrix, and the non-zero rows after reduction become new basis polynomials.
+	MatrixReduction
+)
+
+// A Backend is a representation of the intermediate polynomial in the divisions of [BuchbergerWithOptions] and [BuchbergerHomogeneousWithOptions].
+// The backend only affects performance, and the resulting bases are the same.
+type Backend int
+
+const (
+	// TreeBackend keeps the intermediate polynomial in an ordered tree, as in a [Polynomial].
+	// Each reduction step inserts the terms of a multiple of a basis polynomial into the tree one by one.
+	TreeBackend Backend = iota
+	// GeobucketBackend keeps the intermediate polynomial in geobuckets, and the basis polynomials in sorted term vectors.
+	// Each reduction step merges a multiple of a basis polynomial, which remains sorted, into a bucket of comparable length.
+	// For more details, please see Yan.
+	//
+	// Yan, Thomas. "The geobucket data structure for polynomials." Journal of Symbolic Computation 25.3 (1998): 285-293.
+	GeobucketBackend
+)
+
+// String returns the name of b.
+func (b Backend) String() string {
+	switch b {
+	case TreeBackend:
+		return "TreeBackend"
+	case GeobucketBackend:
+		return "GeobucketBackend"
+	default:
+		return fmt.Sprintf("Backend(%d)", int(b))
+	}
+}
+
+// Stats are statistics of a Gröbner basis computation.
+type Stats struct {
+	// Obstructions is the number of obstructions waiting to be processed.
+	Obstructions int
+	// Basis is the number of polynomials in the current basis.
+	Basis int
+	// Degree is the degree currently being processed by [BuchbergerHomogeneousWithOptions].
+	// It is always zero for [BuchbergerWithOptions].
+	Degree int
+	// Sugar is the sugar degree of the latest obstruction processed by [BuchbergerWithOptions].
+	// It is always zero for [BuchbergerHomogeneousWithOptions].
+	Sugar int
+	// Reductions is the number of S-polynomials that have been reduced.
+	Reductions int
+	// ZeroReductions is the number of S-polynomials that reduced to zero.
+	ZeroReductions int
+	// Removed4b is the number of obstructions pruned by step 4b, Theorem 4.2.22, Xiu Xingqiang.
+	Removed4b int
+	// Removed4c is the number of obstructions pruned by step 4c, Theorem 4.2.22, Xiu Xingqiang.
+	Removed4c int
+	// Removed4d is the number of obstructions pruned by step 4d, Theorem 4.2.22, Xiu Xingqiang.
+	Removed4d int
+}
+
+// monicSorted makes each polynomial in g monic, and sorts g.
+func monicSorted[K Field[K]](g []*Polynomial[K]) []*Polynomial[K] {
+	if len(g) == 0 {
+		return g
+	}
+	r0 := g[0].field.NewZero()
+	for i := range g {
+		lc := g[i].LeadingTerm().Coefficient
+		g[i].mulScalar(r0.Inv(lc), g[i])
+	}
+	slices.SortFunc(g, polynomialCmp[K])
+	return g
 }
 
 func interreduce[K Field[K]](g []*Polynomial[K]) []*Polynomial[K] {
+	g, _, _ = interreduceDone(nil, g, nil)
+	return g
+}
+
+// interreduceDone implements interreduce, and stops early when done is closed.
+// Upon cancellation, the returned polynomials still generate the same ideal as g, but are not fully interreduced.
+// If cofactors is not nil, it is updated alongside g, and returned with the same length as the returned polynomials.
+func interreduceDone[K Field[K]](done <-chan struct{}, g []*Polynomial[K], cofactors []cofactorSum[K]) ([]*Polynomial[K], []cofactorSum[K], error) {
+	var quotient [][]Quotient[K]
+	if cofactors != nil {
+		quotient = [][]Quotient[K]{}
+	}
 	i, s := 0, len(g)
+	var err error
+	// reducer divides by g, and is rebuilt after g changes.
+	var reducer *Reducer[K]
 	for i != s {
 		gi := g[i]
 		if gi == nil {
 			i++
 			continue
 		}
-		f := NewPolynomial(g[0].field, g[0].order).Set(gi)
-		g[i] = nil
-		_, giP := Divide(nil, f, g)
+		if reducer == nil {
+			reducer = NewReducer(slices.Clone(g))
+		}
+		f := NewPolynomial(gi.field, gi.order).Set(gi)
+		var giP *Polynomial[K]
+		quotient, giP, err = reducer.divide(done, quotient, f, i)
+		if err != nil {
+			break
+		}
 
 		switch {
 		case giP.m.Len() == 0:
 			g[i] = nil
+			reducer = nil
 			i++
 		case !giP.Equal(gi):
 			g[i] = giP
+			reducer = nil
+			if cofactors != nil {
+				cofactors[i] = reduceCofactors(cofactors[i], quotient, cofactors)
+			}
 			i = 0
 		default:
-			g[i] = gi
 			i++
 		}
 	}
-	return slices.DeleteFunc(g, func(x *Polynomial[K]) bool { return x == nil })
+
+	if cofactors != nil {
+		k := 0
+		for i := range g {
+			if g[i] != nil {
+				cofactors[k] = cofactors[i]
+				k++
+			}
+		}
+		cofactors = cofactors[:k]
+	}
+	return slices.DeleteFunc(g, func(x *Polynomial[K]) bool { return x == nil }), cofactors, err
 }
 
 func smallestDegreeSet[K Field[K]](g, gd []*Polynomial[K], b, bd []obstruction[K], maxDeg int) ([]*Polynomial[K], []*Polynomial[K], []obstruction[K], []obstruction[K], bool) {
@@ -701,7 +1326,7 @@ func deleteHighDegObs[K Field[K]](b []obstruction[K], basis []*Polynomial[K], ma
 		if b[i].sPolynomial != nil {
 			break
 		}
-		b[i].sPolynomial = sPolynomial(b[i], basis, r0)
+		b[i].sPolynomial = sPolynomial(b[i], basis, r0, nil)
 		if b[i].sPolynomial.Len() == 0 {
 			b[i].removed = true
 			continue
@@ -725,27 +1350,87 @@ type obstruction[K Field[K]] struct {
 	sPolynomial *Polynomial[K]
 
 	removed bool
+
+	// seq is the creation order of the obstruction, starting from 1.
+	seq int
+	// word is iLeft * lt(g_i) * iRight, which equals jLeft * lt(g_j) * jRight.
+	word Monomial
+	// sugar is the sugar degree of the S-polynomial.
+	sugar int
+}
+
+// An obstructionQueue is a priority queue of obstructions ordered by a [Strategy].
+type obstructionQueue[K Field[K]] struct {
+	s        []obstruction[K]
+	order    Order
+	strategy Strategy
+}
+
+func (q *obstructionQueue[K]) Len() int      { return len(q.s) }
+func (q *obstructionQueue[K]) Swap(i, j int) { q.s[i], q.s[j] = q.s[j], q.s[i] }
+func (q *obstructionQueue[K]) Push(x any)    { q.s = append(q.s, x.(obstruction[K])) }
+func (q *obstructionQueue[K]) Pop() any {
+	x := q.s[len(q.s)-1]
+	q.s = q.s[:len(q.s)-1]
+	return x
+}
+
+func (q *obstructionQueue[K]) Less(i, j int) bool {
+	x, y := &q.s[i], &q.s[j]
+	switch q.strategy {
+	case SugarStrategy:
+		if x.sugar != y.sugar {
+			return x.sugar < y.sugar
+		}
+		fallthrough
+	case NormalStrategy:
+		if c := q.order(x.word, y.word); c != 0 {
+			return c < 0
+		}
+	}
+	return x.seq < y.seq
 }
 
-func sPolynomial[K Field[K]](o obstruction[K], g []*Polynomial[K], buf K) *Polynomial[K] {
+// sPolynomial returns the S-polynomial of the obstruction o among g.
+// If gcd is nil, the leading terms of g_i and g_j are made monic, that is the S-polynomial is 1/lc_i*u*g_i*v - 1/lc_j*u'*g_j*v'.
+// Otherwise, the S-polynomial is built fraction-free as lc_j/d*u*g_i*v - lc_i/d*u'*g_j*v', where d is the greatest common divisor of lc_i and lc_j.
+func sPolynomial[K Field[K]](o obstruction[K], g []*Polynomial[K], buf K, gcd func(x, y K) K) *Polynomial[K] {
 	gi, gj := g[o.i], g[o.j]
-	lcgi := gi.LeadingTerm().Coefficient
-	lcgj := gj.LeadingTerm().Coefficient
-	s := NewPolynomial(gi.field, gi.order)
+	ci, cj := sPolynomialCoefficients(gi, gj, buf, gcd)
+	// Use a new field element, since s.field is modified in arithmetic, and gi may be shared among goroutines.
+	s := NewPolynomial(gi.field.NewZero(), gi.order)
 	s.SymbolStringer = gi.SymbolStringer
-	s.add(1, buf.Inv(lcgi), o.iLeft, gi, o.iRight)
-	s.add(-1, buf.Inv(lcgj), o.jLeft, gj, o.jRight)
+	s.add(1, ci, o.iLeft, gi, o.iRight)
+	s.add(-1, cj, o.jLeft, gj, o.jRight)
 	return s
 }
 
-func delRemoved[K Field[K]](sPObs, obs []obstruction[K]) ([]obstruction[K], []obstruction[K]) {
+// sPolynomialCoefficients returns the coefficients by which gi and gj are multiplied in their S-polynomial, see [sPolynomial].
+func sPolynomialCoefficients[K Field[K]](gi, gj *Polynomial[K], buf K, gcd func(x, y K) K) (ci, cj K) {
+	lcgi := gi.LeadingTerm().Coefficient
+	lcgj := gj.LeadingTerm().Coefficient
+	if gcd == nil {
+		return buf.NewZero().Inv(lcgi), buf.NewZero().Inv(lcgj)
+	}
+	d := gcd(lcgi, lcgj)
+	return buf.NewZero().Div(lcgj, d), buf.NewZero().Div(lcgi, d)
+}
+
+func delRemoved[K Field[K]](sPObs, obs []obstruction[K]) ([]obstruction[K], []obstruction[K], int) {
 	spPrevLen := len(sPObs)
 	sPObs = slices.DeleteFunc(sPObs, func(o obstruction[K]) bool { return o.removed })
-	obs = obs[:len(obs)-(spPrevLen-len(sPObs))]
-	return sPObs, obs
+	numRemoved := spPrevLen - len(sPObs)
+	obs = obs[:len(obs)-numRemoved]
+	return sPObs, obs, numRemoved
 }
 
-func addObstructions[K Field[K]](obs []obstruction[K], g []*Polynomial[K], buf *Monomial) []obstruction[K] {
+// addObstructions adds the obstructions of the last polynomial in g to obs.
+// If stats is not nil, the number of obstructions removed by each criterion is added to it.
+func addObstructions[K Field[K]](obs []obstruction[K], g []*Polynomial[K], buf *Monomial, stats *Stats) []obstruction[K] {
+	if stats == nil {
+		stats = &Stats{}
+	}
+
 	// Add sPObs.
 	prevLen := len(obs)
 	obs = overlapObstruction(obs, g)
@@ -753,17 +1438,22 @@ func addObstructions[K Field[K]](obs []obstruction[K], g []*Polynomial[K], buf *
 
 	// Remove from sPObs using step 4b Theorem 4.2.22.
 	remove4b(sPObs, g[0].order)
-	sPObs, obs = delRemoved(sPObs, obs)
+	var numRemoved int
+	sPObs, obs, numRemoved = delRemoved(sPObs, obs)
+	stats.Removed4b += numRemoved
 
 	// Remove from sPObs using step 4c Theorem 4.2.22.
 	b := obs[:prevLen]
 	ltgs := g[len(g)-1].LeadingTerm().Monomial
 	remove4c(sPObs, b, ltgs)
-	sPObs, obs = delRemoved(sPObs, obs)
+	sPObs, obs, numRemoved = delRemoved(sPObs, obs)
+	stats.Removed4c += numRemoved
 
 	// Remove from b using step 4d Theorem 4.2.22.
 	remove4d(b, sPObs, g, buf)
+	prevLen = len(obs)
 	obs = slices.DeleteFunc(obs, func(o obstruction[K]) bool { return o.removed })
+	stats.Removed4d += prevLen - len(obs)
 
 	return obs
 }
@@ -918,8 +1608,9 @@ func shrink[K Field[K]](o obstruction[K]) obstruction[K] {
 			break
 		}
 	}
-	o.iRight = o.iRight[:len(o.iRight)-rightEnd]
-	o.jRight = o.jRight[:len(o.jRight)-rightEnd]
+	iEnd, jEnd := len(o.iRight)-rightEnd, len(o.jRight)-rightEnd
+	o.iRight = o.iRight[:iEnd:iEnd]
+	o.jRight = o.jRight[:jEnd:jEnd]
 
 	return o
 }
@@ -953,7 +1644,7 @@ func leftObstruction[K Field[K]](obs []obstruction[K], i, j int, g []*Polynomial
 		jOverlap := ltgj[jStart:]
 		if monomialEq(iOverlap, jOverlap) {
 			o := obstruction[K]{i: i, j: j}
-			o.iLeft = ltgj[:jStart]
+			o.iLeft = ltgj[:jStart:jStart]
 			o.jRight = ltgi[iEnd:]
 			obs = append(obs, o)
 		}
@@ -984,7 +1675,7 @@ func rightObstruction[K Field[K]](obs []obstruction[K], i, j int, g []*Polynomia
 		if monomialEq(iOverlap, jOverlap) {
 			o := obstruction[K]{i: i, j: j}
 			o.iRight = ltgj[jEnd:]
-			o.jLeft = ltgi[:iStart]
+			o.jLeft = ltgi[:iStart:iStart]
 			obs = append(obs, o)
 		}
 
@@ -1005,7 +1696,7 @@ func centerObstruction[K Field[K]](obs []obstruction[K], i, j int, g []*Polynomi
 			jOverlap := ltgj[jStart:jEnd]
 			if monomialEq(ltgi, jOverlap) {
 				o := obstruction[K]{i: i, j: j}
-				o.iLeft = ltgj[:jStart]
+				o.iLeft = ltgj[:jStart:jStart]
 				o.iRight = ltgj[jEnd:]
 				obs = append(obs, o)
 			}
@@ -1016,7 +1707,7 @@ func centerObstruction[K Field[K]](obs []obstruction[K], i, j int, g []*Polynomi
 			iOverlap := ltgi[iStart:iEnd]
 			if monomialEq(iOverlap, ltgj) {
 				o := obstruction[K]{i: i, j: j}
-				o.jLeft = ltgi[:iStart]
+				o.jLeft = ltgi[:iStart:iStart]
 				o.jRight = ltgi[iEnd:]
 				obs = append(obs, o)
 			}
@@ -1026,11 +1717,23 @@ func centerObstruction[K Field[K]](obs []obstruction[K], i, j int, g []*Polynomi
 	return obs
 }
 
+// errCanceled is returned by internal functions when their done channel is closed.
+var errCanceled = errors.New("canceled")
+
+// degree returns the largest degree among the monomials in x.
+func degree[K Field[K]](x *Polynomial[K]) int {
+	var d int
+	for w := range x.m.All() {
+		d = max(d, w.word().degree)
+	}
+	return d
+}
+
 func homogeneous[K Field[K]](x *Polynomial[K]) bool {
 	for i := range x.m.Len() - 1 {
 		im, _ := x.m.At(i)
 		i1m, _ := x.m.At(i + 1)
-		if len(im) != len(i1m) {
+		if im.word().degree != i1m.word().degree {
 			return false
 		}
 	}
@@ -1038,7 +1741,7 @@ func homogeneous[K Field[K]](x *Polynomial[K]) bool {
 }
 
 func englishSymbolStringer(s Symbol) string {
-	return string((s % 26) - 1 + 'a')
+	return string(rune((s%26)-1) + 'a')
 }
 
 func printSymbol(b *strings.Builder, s Symbol, power int, symbolStringer func(Symbol) string) {
@@ -1069,16 +1772,78 @@ func printMonomial(b *strings.Builder, w Monomial, ss func(Symbol) string) {
 
 func monomialEq(x, y Monomial) bool { return slices.Equal(x, y) }
 
+// key returns w encoded as a string, so that it can be used as a map key.
+// Each symbol is encoded as an unsigned varint, so that a monomial of the first 128 symbols takes only one byte per symbol.
+// Since the encoding is prefix-free, the key of a concatenation of monomials is the concatenation of their keys.
+func (w Monomial) key() string {
+	return string(w.appendKey(make([]byte, 0, len(w))))
+}
+
+// appendKey appends the key of w to b and returns the extended buffer.
+func (w Monomial) appendKey(b []byte) []byte {
+	for _, s := range w {
+		b = binary.AppendUvarint(b, uint64(s))
+	}
+	return b
+}
+
+// monomialFromKey returns the monomial encoded by [Monomial.key].
+func monomialFromKey(k string) Monomial {
+	w := make(Monomial, 0, len(k))
+	var s Symbol
+	var shift uint
+	for i := 0; i < len(k); i++ {
+		s |= Symbol(k[i]&0x7f) << shift
+		if k[i] < 0x80 {
+			w = append(w, s)
+			s, shift = 0, 0
+		} else {
+			shift += 7
+		}
+	}
+	return w
+}
+
+// monomialIndex returns the index of the first occurrence of y in x, or -1 if y is not present in x.
+// The monomials are searched as bytes with [bytes.Index], which is much faster than comparing symbol by symbol, and matches that are not aligned to a symbol are skipped.
 func monomialIndex(x, y Monomial) int {
-	return bytes.Index(x, y)
+	if len(y) == 0 {
+		return 0
+	}
+	xb, yb := symbolBytes(x), symbolBytes(y)
+	for start := 0; ; {
+		i := bytes.Index(xb[start:], yb)
+		if i == -1 {
+			return -1
+		}
+		if i += start; i%symbolSize == 0 {
+			return i / symbolSize
+		}
+		start = i + 1
+	}
+}
+
+// symbolSize is the number of bytes of a [Symbol].
+const symbolSize = int(unsafe.Sizeof(Symbol(0)))
+
+// symbolBytes returns the bytes underlying w.
+func symbolBytes(w Monomial) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(w))), len(w)*symbolSize)
 }
 
 func cutSuffix(x, y Monomial) (Monomial, bool) {
-	return bytes.CutSuffix(x, y)
+	if len(x) < len(y) || !slices.Equal(x[len(x)-len(y):], y) {
+		return x, false
+	}
+	n := len(x) - len(y)
+	return x[:n:n], true
 }
 
 func cutPrefix(x, y Monomial) (Monomial, bool) {
-	return bytes.CutPrefix(x, y)
+	if len(x) < len(y) || !slices.Equal(x[:len(y)], y) {
+		return x, false
+	}
+	return x[len(y):], true
 }
 
 func lexicographic(x, y Monomial) int {
@@ -1126,7 +1891,7 @@ func polynomialCmp[K Field[K]](x, y *Polynomial[K]) int {
 		}
 		xw, _ := x.m.At(x.m.Len() - 1 - i)
 		yw, _ := y.m.At(y.m.Len() - 1 - i)
-		if wo := x.order(xw, yw); wo != 0 {
+		if wo := compareWords(x.order)(xw, yw); wo != 0 {
 			return wo
 		}
 	}
@@ -10,20 +10,45 @@
 package nag
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"slices"
 	"testing"
 )
 
+type buchbergerHomogeneousTest struct {
+	ideal    []*Polynomial[*Rat]
+	maxDeg   int
+	basis    []*Polynomial[*Rat]
+	complete bool
+}
+
 func TestBuchbergerHomogeneous(t *testing.T) {
-	tests := []struct {
-		ideal    []*Polynomial[*Rat]
-		maxDeg   int
-		basis    []*Polynomial[*Rat]
-		complete bool
-	}{
+	for i, test := range buchbergerHomogeneousTests() {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			basis, complete := BuchbergerHomogeneous(test.ideal, test.maxDeg)
+			if len(basis) != len(test.basis) {
+				t.Fatalf("%d %v", len(basis), basis)
+			}
+			for i := range basis {
+				if !basis[i].Equal(test.basis[i]) {
+					t.Errorf("%d %v", i, basis[i])
+				}
+			}
+			if complete != test.complete {
+				t.Errorf("got %v want %v", complete, test.complete)
+			}
+		})
+	}
+}
+
+func buchbergerHomogeneousTests() []buchbergerHomogeneousTest {
+	return []buchbergerHomogeneousTest{
 		// Section 1.2.2 Commutative algebras, Bergman manual.
 		{
 			ideal: []*Polynomial[*Rat]{
@@ -473,11 +498,27 @@ func TestBuchbergerHomogeneous(t *testing.T) {
 			complete: false,
 		},
 	}
+}
 
-	for i, test := range tests {
+type buchbergerTest struct {
+	ideal    []*Polynomial[*Rat]
+	maxiter  int
+	basis    []*Polynomial[*Rat]
+	complete bool
+	long     bool
+}
+
+// testBuchbergerCases runs compute on the cases of [buchbergerTests], and checks that it returns their bases.
+// If completeOnly is true, cases with incomplete bases are skipped, since the partial bases of other algorithms may differ from those of [Buchberger].
+// The long cases are always skipped.
+func testBuchbergerCases(t *testing.T, completeOnly bool, compute func(t *testing.T, test buchbergerTest) (basis []*Polynomial[*Rat], complete bool)) {
+	for i, test := range buchbergerTests() {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
 			t.Parallel()
-			basis, complete := BuchbergerHomogeneous(test.ideal, test.maxDeg)
+			if test.long || (completeOnly && !test.complete) {
+				t.Skip()
+			}
+			basis, complete := compute(t, test)
 			if len(basis) != len(test.basis) {
 				t.Fatalf("%d %v", len(basis), basis)
 			}
@@ -494,13 +535,33 @@ func TestBuchbergerHomogeneous(t *testing.T) {
 }
 
 func TestBuchberger(t *testing.T) {
-	tests := []struct {
-		ideal    []*Polynomial[*Rat]
-		maxiter  int
-		basis    []*Polynomial[*Rat]
-		complete bool
-		long     bool
-	}{
+	for i, test := range buchbergerTests() {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if testing.Short() && test.long {
+				t.Skip()
+			}
+			ideal := make([]*Polynomial[*Rat], len(test.ideal))
+			copy(ideal, test.ideal)
+
+			basis, complete := Buchberger(ideal, test.maxiter)
+			if len(basis) != len(test.basis) {
+				t.Fatalf("%d %v", len(basis), basis)
+			}
+			for i := range basis {
+				if !basis[i].Equal(test.basis[i]) {
+					t.Errorf("%d %v", i, basis[i])
+				}
+			}
+			if complete != test.complete {
+				t.Errorf("got %v want %v", complete, test.complete)
+			}
+		})
+	}
+}
+
+func buchbergerTests() []buchbergerTest {
+	return []buchbergerTest{
 		// Example 5.12, Mora.
 		{
 			ideal: []*Polynomial[*Rat]{
@@ -1885,32 +1946,507 @@ func TestBuchberger(t *testing.T) {
 			long:     true,
 		},
 	}
+}
+
+func TestBuchbergerContext(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	tests := []struct {
+		ideal   []string
+		maxiter int
+		cancel  bool
+		// cancelAfter, if positive, is the number of reductions after which the context is cancelled.
+		cancelAfter int
+		workers     int
+		basis       []string
+		complete    bool
+		err         error
+	}{
+		// Example 5.12, Mora.
+		{
+			ideal:    []string{"aba - b", "bab - b"},
+			maxiter:  50,
+			basis:    []string{"ba-b^2", "ab-b^2", "b^3-b"},
+			complete: true,
+		},
+		{
+			ideal:   []string{"2aba - 2b", "bab - b"},
+			maxiter: 10,
+			cancel:  true,
+			basis:   []string{"bab-b", "aba-b"},
+			err:     context.Canceled,
+		},
+		// The braid relation has an infinite Gröbner basis.
+		{
+			ideal:       []string{"aba - bab"},
+			maxiter:     math.MaxInt,
+			cancelAfter: 20,
+			err:         context.Canceled,
+		},
+		{
+			ideal:       []string{"aba - bab"},
+			maxiter:     math.MaxInt,
+			cancelAfter: 20,
+			workers:     4,
+			err:         context.Canceled,
+		},
+	}
 
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
 			t.Parallel()
-			if testing.Short() && test.long {
-				t.Skip()
+			ideal := make([]*Polynomial[*Rat], 0, len(test.ideal))
+			for _, s := range test.ideal {
+				ideal = append(ideal, parseMust(variables, Deglex, s))
 			}
-			ideal := make([]*Polynomial[*Rat], len(test.ideal))
-			copy(ideal, test.ideal)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if test.cancel {
+				cancel()
+			}
+			opts := &Options{Workers: test.workers, Observer: func(s Stats) {
+				if test.cancelAfter > 0 && s.Reductions >= test.cancelAfter {
+					cancel()
+				}
+			}}
 
-			basis, complete := Buchberger(ideal, test.maxiter)
+			basis, complete, stats, err := BuchbergerWithOptions(ctx, ideal, test.maxiter, opts)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v want %v", err, test.err)
+			}
+			if complete != test.complete {
+				t.Errorf("got %v want %v", complete, test.complete)
+			}
+			if test.basis == nil {
+				// The run stops at the first reduction after cancellation, and its basis should contain more than the input.
+				if stats.Reductions != test.cancelAfter {
+					t.Errorf("got %d want %d", stats.Reductions, test.cancelAfter)
+				}
+				if len(basis) <= len(test.ideal) {
+					t.Fatalf("%v", basis)
+				}
+				return
+			}
 			if len(basis) != len(test.basis) {
 				t.Fatalf("%d %v", len(basis), basis)
 			}
 			for i := range basis {
-				if !basis[i].Equal(test.basis[i]) {
-					t.Errorf("%d %v", i, basis[i])
+				if want := parseMust(variables, Deglex, test.basis[i]); !basis[i].Equal(want) {
+					t.Errorf("%d %v %v", i, basis[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuchbergerHomogeneousContext(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "aba - bab")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel after a fixed number of reductions, since the braid relation has an infinite Gröbner basis.
+	const cancelAfter = 20
+	opts := &Options{Observer: func(s Stats) {
+		if s.Reductions >= cancelAfter {
+			cancel()
+		}
+	}}
+
+	basis, complete, stats, err := BuchbergerHomogeneousWithOptions(ctx, ideal, math.MaxInt, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("%v", err)
+	}
+	if complete {
+		t.Errorf("complete")
+	}
+	if stats.Reductions < cancelAfter {
+		t.Errorf("%d", stats.Reductions)
+	}
+	if len(basis) <= len(ideal) {
+		t.Errorf("%v", basis)
+	}
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		variables   map[string]Symbol
+		ideal       []string
+		homogeneous bool
+		maxiter     int
+		maxDeg      int
+		stats       Stats
+		numObserved int
+	}{
+		// Example 5.12, Mora.
+		{
+			variables:   map[string]Symbol{"a": 2, "b": 1},
+			ideal:       []string{"aba - b", "bab - b"},
+			maxiter:     50,
+			stats:       Stats{Basis: 3, Sugar: 7, Reductions: 11, ZeroReductions: 7, Removed4b: 15, Removed4d: 2},
+			numObserved: 11,
+		},
+		{
+			variables:   map[string]Symbol{"x": 1, "y": 2, "z": 3},
+			ideal:       []string{"x^2 - 2y^2", "xy - 3z^2"},
+			homogeneous: true,
+			maxDeg:      5,
+			stats:       Stats{Basis: 5, Degree: 5, Reductions: 7, ZeroReductions: 4},
+			numObserved: 9,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := make([]*Polynomial[*Rat], 0, len(test.ideal))
+			for _, s := range test.ideal {
+				ideal = append(ideal, parseMust(test.variables, Deglex, s))
+			}
+			var observed []Stats
+			opts := &Options{Observer: func(s Stats) { observed = append(observed, s) }}
+
+			var stats Stats
+			var err error
+			if test.homogeneous {
+				_, _, stats, err = BuchbergerHomogeneousWithOptions(context.Background(), ideal, test.maxDeg, opts)
+			} else {
+				_, _, stats, err = BuchbergerWithOptions(context.Background(), ideal, test.maxiter, opts)
+			}
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if stats != test.stats {
+				t.Errorf("got %+v want %+v", stats, test.stats)
+			}
+			if len(observed) != test.numObserved {
+				t.Fatalf("%d %+v", len(observed), observed)
+			}
+			// Counters should never decrease.
+			for j := 1; j < len(observed); j++ {
+				prev, cur := observed[j-1], observed[j]
+				if cur.Reductions < prev.Reductions || cur.ZeroReductions < prev.ZeroReductions || cur.Removed4b < prev.Removed4b || cur.Removed4c < prev.Removed4c || cur.Removed4d < prev.Removed4d || cur.Degree < prev.Degree {
+					t.Errorf("%d %+v %+v", j, prev, cur)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	for _, workers := range []int{2, 4, 8} {
+		t.Run(fmt.Sprintf("%d", workers), func(t *testing.T) {
+			t.Parallel()
+			// Results should be the same as reducing one S-polynomial at a time, including those truncated by maxiter.
+			testBuchbergerCases(t, false, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+				opts := &Options{Workers: workers}
+				basis, complete, stats, err := BuchbergerWithOptions(context.Background(), test.ideal, test.maxiter, opts)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				_, _, want, err := BuchbergerWithOptions(context.Background(), test.ideal, test.maxiter, nil)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				if stats != want {
+					t.Errorf("got %+v want %+v", stats, want)
+				}
+
+				for _, maxIter := range []int{1, 3, 7, 15} {
+					truncated, _, _, err := BuchbergerWithOptions(context.Background(), test.ideal, maxIter, opts)
+					if err != nil {
+						t.Fatalf("%+v", err)
+					}
+					want, _ := Buchberger(test.ideal, maxIter)
+					if !polynomialsEqual(truncated, want) {
+						t.Errorf("%d got %v want %v", maxIter, truncated, want)
+					}
+				}
+				return basis, complete
+			})
+		})
+	}
+}
+
+func TestStrategy(t *testing.T) {
+	strategies := []Strategy{FIFOStrategy, NormalStrategy, SugarStrategy}
+	for _, strategy := range strategies {
+		t.Run(fmt.Sprintf("%d", strategy), func(t *testing.T) {
+			t.Parallel()
+			// Only complete bases are independent of the order in which obstructions are processed.
+			testBuchbergerCases(t, true, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+				opts := &Options{Strategy: strategy}
+				basis, complete, _, err := BuchbergerWithOptions(context.Background(), test.ideal, math.MaxInt, opts)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				return basis, complete
+			})
+		})
+	}
+}
+
+func TestMaxSugar(t *testing.T) {
+	t.Run("homogeneous", func(t *testing.T) {
+		t.Parallel()
+		for i, test := range buchbergerHomogeneousTests() {
+			t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+				t.Parallel()
+				opts := &Options{MaxSugar: test.maxDeg}
+				basis, complete, _, err := BuchbergerWithOptions(context.Background(), test.ideal, math.MaxInt, opts)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				if len(basis) != len(test.basis) {
+					t.Fatalf("%d %v", len(basis), basis)
+				}
+				for i := range basis {
+					if !basis[i].Equal(test.basis[i]) {
+						t.Errorf("%d %v", i, basis[i])
+					}
+				}
+				if complete != test.complete {
+					t.Errorf("%v", complete)
+				}
+			})
+		}
+	})
+
+	t.Run("inhomogeneous", func(t *testing.T) {
+		t.Parallel()
+		variables := map[string]Symbol{"x": 2, "y": 1}
+		ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")}
+		tests := []struct {
+			maxSugar int
+			basis    []*Polynomial[*Rat]
+			complete bool
+		}{
+			{
+				maxSugar: 2,
+				basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "xy - x"), parseMust(variables, Deglex, "x^2 - y")},
+				complete: false,
+			},
+			{
+				maxSugar: 3,
+				basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "y^2 - y"), parseMust(variables, Deglex, "yx - x"), parseMust(variables, Deglex, "xy - x"), parseMust(variables, Deglex, "x^2 - y")},
+				complete: false,
+			},
+			{
+				maxSugar: 4,
+				basis:    []*Polynomial[*Rat]{parseMust(variables, Deglex, "y^2 - y"), parseMust(variables, Deglex, "yx - x"), parseMust(variables, Deglex, "xy - x"), parseMust(variables, Deglex, "x^2 - y")},
+				complete: true,
+			},
+		}
+		for i, test := range tests {
+			opts := &Options{MaxSugar: test.maxSugar}
+			basis, complete, stats, err := BuchbergerWithOptions(context.Background(), ideal, math.MaxInt, opts)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if len(basis) != len(test.basis) {
+				t.Fatalf("%d %d %v", i, len(basis), basis)
+			}
+			for j := range basis {
+				if !basis[j].Equal(test.basis[j]) {
+					t.Errorf("%d %d %v", i, j, basis[j])
 				}
 			}
 			if complete != test.complete {
-				t.Errorf("got %v want %v", complete, test.complete)
+				t.Errorf("%d %v", i, complete)
+			}
+			if test.maxSugar > 0 && stats.Sugar > test.maxSugar {
+				t.Errorf("%d %+v", i, stats)
+			}
+		}
+	})
+}
+
+func TestIsMember(t *testing.T) {
+	variables := map[string]Symbol{"x": 2, "y": 1}
+	basis, _ := Buchberger([]*Polynomial[*Rat]{parseMust(variables, Deglex, "x^2 - y"), parseMust(variables, Deglex, "xy - x")}, math.MaxInt)
+	tests := []struct {
+		f      string
+		member bool
+	}{
+		{f: "x^2 - y", member: true},
+		{f: "yx - x", member: true},
+		{f: "x^3 - xy + 2y^2x - 2yx", member: true},
+		{f: "x", member: false},
+		{f: "y^2 - 1", member: false},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			f := parseMust(variables, Deglex, test.f)
+			member, quotient := IsMember(f, basis)
+			if member != test.member {
+				t.Fatalf("got %v want %v", member, test.member)
+			}
+			if want := parseMust(variables, Deglex, test.f); !f.Equal(want) {
+				t.Errorf("f modified %v", f)
+			}
+			if !member {
+				return
+			}
+			sum := NewPolynomial(f.field.NewZero(), f.order)
+			for j := range quotient {
+				for _, q := range quotient[j] {
+					sum.add(1, q.Coefficient, q.Left, basis[j], q.Right)
+				}
+			}
+			if !sum.Equal(f) {
+				t.Errorf("got %v want %v", sum, f)
+			}
+		})
+	}
+}
+
+func TestFractionFree(t *testing.T) {
+	testBuchbergerCases(t, false, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+		opts := &Options{FractionFree: true}
+		basis, complete, _, err := BuchbergerWithOptions(context.Background(), test.ideal, test.maxiter, opts)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return basis, complete
+	})
+}
+
+func TestGeobucketBackend(t *testing.T) {
+	for _, fractionFree := range []bool{false, true} {
+		t.Run(fmt.Sprintf("%v", fractionFree), func(t *testing.T) {
+			t.Parallel()
+			testBuchbergerCases(t, false, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+				opts := &Options{Backend: GeobucketBackend, FractionFree: fractionFree}
+				basis, complete, _, err := BuchbergerWithOptions(context.Background(), test.ideal, test.maxiter, opts)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				return basis, complete
+			})
+		})
+	}
+}
+
+// BenchmarkBuchberger runs the cases of [TestBuchberger], except the long ones.
+func BenchmarkBuchberger(b *testing.B) {
+	tests := buchbergerTests()
+	for b.Loop() {
+		for _, test := range tests {
+			if test.long {
+				continue
+			}
+			Buchberger(test.ideal, test.maxiter)
+		}
+	}
+}
+
+// BenchmarkMulDivide multiplies and divides polynomials with many terms, whose monomials are interned by every addition.
+func BenchmarkMulDivide(b *testing.B) {
+	variables := map[string]Symbol{"a": 1, "b": 2, "c": 3}
+	x := parseMust(variables, Deglex, "a + 2b - c + 1")
+	g := []*Polynomial[*Rat]{parseMust(variables, Deglex, "ba - ab"), parseMust(variables, Deglex, "ca - ac"), parseMust(variables, Deglex, "cb - bc")}
+	for b.Loop() {
+		z := NewPolynomial(NewRat(0, 1), Deglex).Pow(x, 6)
+		Divide(nil, z, g)
+	}
+}
+
+// BenchmarkBackend compares the backends of division on the cases of [TestBuchberger].
+func BenchmarkBackend(b *testing.B) {
+	for _, backend := range []Backend{TreeBackend, GeobucketBackend} {
+		b.Run(backend.String(), func(b *testing.B) {
+			tests := buchbergerTests()
+			opts := &Options{Backend: backend}
+			for b.Loop() {
+				for _, test := range tests {
+					if test.long {
+						continue
+					}
+					if _, _, _, err := BuchbergerWithOptions(context.Background(), test.ideal, test.maxiter, opts); err != nil {
+						b.Fatalf("%+v", err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkWorkers compares the numbers of workers on case 18 of [TestBuchberger].
+// Reductions put back after a remainder is added to the basis are wasted, so larger batches are slower on a single CPU.
+// Run it with -cpu to compare the numbers of workers with the numbers of CPUs.
+func BenchmarkWorkers(b *testing.B) {
+	test := buchbergerTests()[18]
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("%d", workers), func(b *testing.B) {
+			opts := &Options{Workers: workers}
+			for b.Loop() {
+				if _, _, _, err := BuchbergerWithOptions(context.Background(), test.ideal, test.maxiter, opts); err != nil {
+					b.Fatalf("%+v", err)
+				}
 			}
 		})
 	}
 }
 
+// BenchmarkFractionFree compares reduction with and without fractions on the ideal of [Example_minimal_polynomial].
+func BenchmarkFractionFree(b *testing.B) {
+	variables := map[string]Symbol{"x": 4, "y": 3, "z": 2, "α": 1}
+	var ideal []*Polynomial[*Rat]
+	for _, s := range []string{"x^2 - 2", "y^2 - 3", "z^2 - 5", "α - x - y - z", "xy - yx", "xz - zx", "xα - αx", "yz - zy", "yα - αy", "zα - αz"} {
+		ideal = append(ideal, parseMust(variables, ElimOrder(), s))
+	}
+	for _, fractionFree := range []bool{false, true} {
+		b.Run(fmt.Sprintf("%v", fractionFree), func(b *testing.B) {
+			opts := &Options{FractionFree: fractionFree}
+			for b.Loop() {
+				if _, _, _, err := BuchbergerWithOptions(context.Background(), ideal, 50, opts); err != nil {
+					b.Fatalf("%+v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRatGCD(t *testing.T) {
+	tests := []struct {
+		x, y *Rat
+		gcd  *Rat
+	}{
+		{x: NewRat(12, 1), y: NewRat(18, 1), gcd: NewRat(6, 1)},
+		{x: NewRat(-12, 1), y: NewRat(18, 1), gcd: NewRat(6, 1)},
+		{x: NewRat(0, 1), y: NewRat(-5, 1), gcd: NewRat(5, 1)},
+		{x: NewRat(0, 1), y: NewRat(0, 1), gcd: NewRat(0, 1)},
+		{x: NewRat(1, 2), y: NewRat(1, 3), gcd: NewRat(1, 6)},
+		{x: NewRat(3, 4), y: NewRat(9, 10), gcd: NewRat(3, 20)},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if gcd := NewRat(7, 1).GCD(test.x, test.y); !gcd.Equal(test.gcd) {
+				t.Errorf("got %v want %v", gcd, test.gcd)
+			}
+		})
+	}
+}
+
+func TestDivideContext(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "z": 1}
+	g := []*Polynomial[*Rat]{parseMust(variables, Deglex, "xy + x"), parseMust(variables, Deglex, "x^2 + xz")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, remainder, err := DivideContext(ctx, nil, parseMust(variables, Deglex, "zx^2yx"), g)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if want := parseMust(variables, Deglex, "zxzx"); !remainder.Equal(want) {
+		t.Errorf("got %v want %v", remainder, want)
+	}
+
+	cancel()
+	if _, _, err := DivideContext(ctx, nil, parseMust(variables, Deglex, "zx^2yx"), g); !errors.Is(err, context.Canceled) {
+		t.Errorf("%v", err)
+	}
+}
+
 func TestAddObstructions(t *testing.T) {
 	tests := []struct {
 		b   []obstruction[*Rat]
@@ -2024,7 +2560,7 @@ func TestAddObstructions(t *testing.T) {
 			b := make([]obstruction[*Rat], len(test.b))
 			copy(b, test.b)
 			buf := &Monomial{}
-			obs := addObstructions(b, test.g, buf)
+			obs := addObstructions(b, test.g, buf, nil)
 
 			if len(obs) != len(test.obs) {
 				t.Fatalf("%v", obs)
@@ -3355,6 +3891,16 @@ func TestOrder(t *testing.T) {
 			order:  Deglex,
 			sorted: []Monomial{{}, {1}, {2}, {1, 1}, {1, 2}, {2, 1}, {2, 2}, {1, 1, 1}, {1, 1, 2}, {1, 2, 1}, {1, 2, 2}, {2, 1, 1}, {2, 1, 2}, {2, 2, 1}, {2, 2, 2}},
 		},
+		{
+			words:  []Monomial{{256, 1}, {1, 256}, {255}, {1 << 20}, {1, 1}, {256}},
+			order:  Deglex,
+			sorted: []Monomial{{255}, {256}, {1 << 20}, {1, 1}, {1, 256}, {256, 1}},
+		},
+		{
+			words:  []Monomial{{300, 1}, {2, 2, 2}, {1, 300}, {299, 299}},
+			order:  ElimOrder(),
+			sorted: []Monomial{{2, 2, 2}, {299, 299}, {1, 300}, {300, 1}},
+		},
 	}
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
@@ -3367,6 +3913,90 @@ func TestOrder(t *testing.T) {
 	}
 }
 
+func TestMonomialKey(t *testing.T) {
+	tests := []struct {
+		w   Monomial
+		len int
+	}{
+		{w: Monomial{}, len: 0},
+		{w: Monomial{0, 1, 127}, len: 3},
+		{w: Monomial{128, 1}, len: 3},
+		{w: Monomial{300, 16383, 16384}, len: 7},
+		{w: Monomial{1<<32 - 1, 0}, len: 6},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			k := test.w.key()
+			if len(k) != test.len {
+				t.Errorf("len(%q): got %d want %d", k, len(k), test.len)
+			}
+			if w := monomialFromKey(k); !monomialEq(w, test.w) {
+				t.Errorf("got %v want %v", w, test.w)
+			}
+			// The key of a concatenation is the concatenation of the keys.
+			ww := append(slices.Clone(test.w), test.w...)
+			if ww.key() != k+k {
+				t.Errorf("%v: got %q want %q", ww, ww.key(), k+k)
+			}
+		})
+	}
+}
+
+func TestMonomialIndex(t *testing.T) {
+	tests := []struct {
+		x, y Monomial
+		i    int
+	}{
+		{x: Monomial{1, 2, 3}, y: Monomial{}, i: 0},
+		{x: Monomial{1, 2, 3}, y: Monomial{2, 3}, i: 1},
+		{x: Monomial{1, 2, 3}, y: Monomial{3, 2}, i: -1},
+		{x: Monomial{2}, y: Monomial{2, 2}, i: -1},
+		// The bytes of 1 occur within those of 256 and 0, but not aligned to a symbol.
+		{x: Monomial{256, 0}, y: Monomial{1}, i: -1},
+		{x: Monomial{256, 0, 1}, y: Monomial{1}, i: 2},
+		{x: Monomial{1 << 24, 1 << 8, 1 << 24, 1}, y: Monomial{1 << 24, 1}, i: 2},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			if idx := monomialIndex(test.x, test.y); idx != test.i {
+				t.Errorf("got %d want %d", idx, test.i)
+			}
+		})
+	}
+}
+
+func TestBuchbergerManySymbols(t *testing.T) {
+	t.Parallel()
+	// The ideal a_{i+1} - a_i^2, a_1^2 - a_1 over 500 variables has the basis a_{i+1} - a_1, a_1^2 - a_1.
+	const n = 500
+	variables := make(map[string]Symbol, n)
+	for i := range n {
+		variables[fmt.Sprintf("{a_{%d}}", i+1)] = Symbol(i + 1)
+	}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "{a_{1}}^2-{a_{1}}")}
+	for i := 1; i < n; i++ {
+		ideal = append(ideal, parseMust(variables, Deglex, fmt.Sprintf("{a_{%d}}-{a_{%d}}^2", i+1, i)))
+	}
+
+	basis, complete := Buchberger(ideal, 5000)
+	if !complete {
+		t.Fatalf("incomplete")
+	}
+	if len(basis) != n {
+		t.Fatalf("got %d polynomials want %d", len(basis), n)
+	}
+	for i := 1; i < n; i++ {
+		if got, want := basis[i-1].String(), fmt.Sprintf("{a_{%d}}-{a_{1}}", i+1); got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+	if got, want := basis[n-1].String(), "{a_{1}}^2-{a_{1}}"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	log.SetFlags(log.Lmicroseconds | log.Llongfile | log.LstdFlags)
@@ -0,0 +1,167 @@
+package nag
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Degrevlex compares x, y by first comparing their degrees, and in case of a tie compares their symbols from right to left, where the monomial with the smaller symbol at the first difference is the larger one.
+// It is the noncommutative analogue of the [graded reverse lexicographic order].
+// Like [Deglex], it is harmonious, that is, monomials of larger degrees are larger.
+// It is the mirror image of [Deglex] with the order of symbols reversed, so that the Gröbner basis of an ideal under Degrevlex is the reversal of the basis under Deglex of the ideal with reversed monomials and reversed symbols.
+//
+// [graded reverse lexicographic order]: https://en.wikipedia.org/wiki/Monomial_order#Graded_reverse_lexicographic_order
+func Degrevlex(x, y Monomial) int {
+	if c := cmp.Compare(len(x), len(y)); c != 0 {
+		return c
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		if c := cmp.Compare(x[i], y[i]); c != 0 {
+			return -c
+		}
+	}
+	return 0
+}
+
+// RightDeglex compares x, y by first comparing their degrees, and in case of a tie applies the lexicographic order reading from right to left.
+// It is the mirror image of [Deglex], which reads from left to right, so that the Gröbner basis of an ideal under RightDeglex is the reversal of the basis of the reversed ideal under Deglex.
+func RightDeglex(x, y Monomial) int {
+	if c := cmp.Compare(len(x), len(y)); c != 0 {
+		return c
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		if c := cmp.Compare(x[i], y[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// WeightedDeglex returns a monomial order that first compares the weighted degrees of monomials, and in case of a tie applies the lexicographic order.
+// The weighted degree of a monomial is the sum of the weights of its symbols, where symbols not in weights have weight 1.
+// Since the weighted degree is additive, the order is compatible with multiplication, and since weights are positive, there are finitely many monomials of each weighted degree, so that the order is a well-order.
+// WeightedDeglex panics if a weight is not positive.
+func WeightedDeglex(weights map[Symbol]int) Order {
+	for s, wt := range weights {
+		if wt <= 0 {
+			panic(fmt.Sprintf("non-positive weight %d of symbol %d", wt, s))
+		}
+	}
+	w := newWeight(weights, 1)
+	return func(x, y Monomial) int {
+		if c := cmp.Compare(w.degree(x), w.degree(y)); c != 0 {
+			return c
+		}
+		return lexicographic(x, y)
+	}
+}
+
+// MatrixOrder returns a monomial order that compares the weighted degrees of monomials for each row of weights in turn, and in case of a tie applies tie.
+// The weighted degree of a monomial in a row is the sum of the weights of its symbols, where symbols not in the row have weight 0.
+// A nil tie is equivalent to [Deglex].
+//
+// The order is admissible, provided that tie is admissible, and the first nonzero weight of every symbol among the rows is positive.
+// The latter ensures that there is no infinite descending chain of monomials, and MatrixOrder panics if it is violated.
+func MatrixOrder(weights []map[Symbol]int, tie Order) Order {
+	if tie == nil {
+		tie = Deglex
+	}
+	symbols := make(map[Symbol]bool)
+	for _, row := range weights {
+		for _, s := range slices.Sorted(maps.Keys(row)) {
+			wt := row[s]
+			if wt == 0 || symbols[s] {
+				continue
+			}
+			if wt < 0 {
+				panic(fmt.Sprintf("first nonzero weight %d of symbol %d is negative", wt, s))
+			}
+			symbols[s] = true
+		}
+	}
+
+	rows := make([]weight, len(weights))
+	for i, row := range weights {
+		rows[i] = newWeight(row, 0)
+	}
+	return func(x, y Monomial) int {
+		for _, w := range rows {
+			if c := cmp.Compare(w.degree(x), w.degree(y)); c != 0 {
+				return c
+			}
+		}
+		return tie(x, y)
+	}
+}
+
+// BlockOrder returns an elimination order for the blocks of symbols.
+// It first compares the degrees of monomials in the symbols of blocks[0], then those in blocks[1] and so on, and in case of a tie applies tie.
+// A nil tie is equivalent to [Deglex].
+//
+// Under this order, a monomial containing symbols of blocks[0] is larger than all monomials without them.
+// Therefore, the polynomials of a Gröbner basis without the symbols of blocks[0] form a Gröbner basis of the intersection of the ideal with the subalgebra generated by the other symbols.
+// This eliminates all symbols of a block at once, whereas [ElimOrder] eliminates symbols one at a time.
+// BlockOrder panics if a symbol appears in more than one block.
+func BlockOrder(tie Order, blocks ...[]Symbol) Order {
+	weights := make([]map[Symbol]int, len(blocks))
+	seen := make(map[Symbol]bool)
+	for i, block := range blocks {
+		weights[i] = make(map[Symbol]int, len(block))
+		for _, s := range block {
+			if seen[s] {
+				panic(fmt.Sprintf("symbol %d in more than one block", s))
+			}
+			seen[s] = true
+			weights[i][s] = 1
+		}
+	}
+	return MatrixOrder(weights, tie)
+}
+
+// A weight assigns integer weights to symbols.
+type weight struct {
+	// dense are the weights of symbols less than len(dense).
+	dense []int
+	// sparse are the weights of the remaining symbols.
+	sparse map[Symbol]int
+	// rest is the weight of symbols in neither dense nor sparse.
+	rest int
+}
+
+// maxDenseSymbol bounds the symbols whose weights are stored in a slice, which is faster to look up than a map.
+const maxDenseSymbol = 1 << 10
+
+func newWeight(weights map[Symbol]int, rest int) weight {
+	w := weight{sparse: make(map[Symbol]int), rest: rest}
+	for s, wt := range weights {
+		if s >= maxDenseSymbol {
+			w.sparse[s] = wt
+			continue
+		}
+		for Symbol(len(w.dense)) <= s {
+			w.dense = append(w.dense, rest)
+		}
+		w.dense[s] = wt
+	}
+	return w
+}
+
+// degree returns the weighted degree of x.
+func (w weight) degree(x Monomial) int {
+	var d int
+	for _, s := range x {
+		switch {
+		case int(s) < len(w.dense):
+			d += w.dense[s]
+		default:
+			wt, ok := w.sparse[s]
+			if !ok {
+				wt = w.rest
+			}
+			d += wt
+		}
+	}
+	return d
+}
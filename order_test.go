@@ -0,0 +1,219 @@
+package nag
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestWeightedOrders(t *testing.T) {
+	tests := []struct {
+		words  []Monomial
+		order  Order
+		sorted []Monomial
+	}{
+		{
+			words:  []Monomial{{2}, {1, 1, 1}, {1, 2}, {2, 1}, {1, 1}, {1}},
+			order:  WeightedDeglex(map[Symbol]int{2: 3}),
+			sorted: []Monomial{{1}, {1, 1}, {1, 1, 1}, {2}, {1, 2}, {2, 1}},
+		},
+		{
+			words:  []Monomial{{2000, 1}, {2000}, {1, 1, 1}, {1, 2000}},
+			order:  WeightedDeglex(map[Symbol]int{2000: 2}),
+			sorted: []Monomial{{2000}, {1, 1, 1}, {1, 2000}, {2000, 1}},
+		},
+		{
+			// Compare first by the degree in 3, then by the weighted degree in 1 and 2, and then by the negated degree in 2.
+			words:  []Monomial{{3}, {1, 1, 2}, {2}, {1}, {1, 1}, {1, 2, 2}, {2, 1, 1, 1, 1}},
+			order:  MatrixOrder([]map[Symbol]int{{3: 1}, {1: 1, 2: 2}, {2: -1}}, nil),
+			sorted: []Monomial{{1}, {2}, {1, 1}, {1, 1, 2}, {1, 2, 2}, {2, 1, 1, 1, 1}, {3}},
+		},
+		{
+			words:  []Monomial{{1, 1, 1}, {3}, {2, 1}, {1, 2}, {4}, {3, 4}},
+			order:  BlockOrder(nil, []Symbol{3, 4}, []Symbol{2}),
+			sorted: []Monomial{{1, 1, 1}, {1, 2}, {2, 1}, {3}, {4}, {3, 4}},
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			slices.SortFunc(test.words, test.order)
+			if !slices.EqualFunc(test.words, test.sorted, monomialEq) {
+				t.Errorf("%v", test.words)
+			}
+			if err := checkAdmissible(test.order, []Symbol{1, 2, 3, 4}, 3); err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+	}
+}
+
+func TestWeightedOrdersInadmissible(t *testing.T) {
+	tests := []struct {
+		order func() Order
+	}{
+		{order: func() Order { return WeightedDeglex(map[Symbol]int{1: 1, 2: 0}) }},
+		{order: func() Order { return WeightedDeglex(map[Symbol]int{1: -1}) }},
+		{order: func() Order { return MatrixOrder([]map[Symbol]int{{1: 1, 2: 0}, {2: -1}}, nil) }},
+		{order: func() Order { return BlockOrder(nil, []Symbol{1, 2}, []Symbol{3, 1}) }},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic")
+				}
+			}()
+			test.order()
+		})
+	}
+}
+
+func TestRightToLeftOrders(t *testing.T) {
+	tests := []struct {
+		words  []Monomial
+		order  Order
+		sorted []Monomial
+	}{
+		{
+			words:  []Monomial{{1, 2}, {2, 1}, {2, 2}, {1, 1}, {2}, {1, 1, 1}, {}},
+			order:  Degrevlex,
+			sorted: []Monomial{{}, {2}, {2, 2}, {1, 2}, {2, 1}, {1, 1}, {1, 1, 1}},
+		},
+		{
+			words:  []Monomial{{1, 2}, {2, 1}, {2, 2}, {1, 1}, {2}, {1, 1, 1}, {}},
+			order:  RightDeglex,
+			sorted: []Monomial{{}, {2}, {1, 1}, {2, 1}, {1, 2}, {2, 2}, {1, 1, 1}},
+		},
+		{
+			words:  []Monomial{{3, 1, 2}, {1, 3, 2}, {2, 2, 1}, {1, 1, 3}},
+			order:  Degrevlex,
+			sorted: []Monomial{{1, 1, 3}, {1, 3, 2}, {3, 1, 2}, {2, 2, 1}},
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			slices.SortFunc(test.words, test.order)
+			if !slices.EqualFunc(test.words, test.sorted, monomialEq) {
+				t.Errorf("%v", test.words)
+			}
+			if err := checkAdmissible(test.order, []Symbol{1, 2, 3}, 3); err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+	}
+}
+
+func TestRightToLeftOrdersBuchberger(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2, "c": 3}
+	// reverse reverses a monomial, which turns RightDeglex into Deglex.
+	reverse := func(w Monomial) Monomial {
+		w = slices.Clone(w)
+		slices.Reverse(w)
+		return w
+	}
+	// revlex additionally swaps the order of symbols, which turns Degrevlex into Deglex.
+	revlex := func(w Monomial) Monomial {
+		w = reverse(w)
+		for i := range w {
+			w[i] = 4 - w[i]
+		}
+		return w
+	}
+	tests := []struct {
+		ideal []string
+	}{
+		{ideal: []string{"aba - b", "bab - b"}},
+		{ideal: []string{"a^2 - 1", "b^3 - 1", "(ab)^3 - 1"}},
+		{ideal: []string{"ab - ba", "ac - ca", "bc - cb", "a^2 - b", "b^2 - c"}},
+		{ideal: []string{"a^2 - 1", "b^2 - 1", "c^2 - 1", "aba - bab", "bcb - cbc", "ac - ca"}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			for _, o := range []struct {
+				order Order
+				f     func(Monomial) Monomial
+			}{{order: RightDeglex, f: reverse}, {order: Degrevlex, f: revlex}} {
+				ideal := make([]*Polynomial[*Rat], len(test.ideal))
+				mirrored := make([]*Polynomial[*Rat], len(test.ideal))
+				for j, s := range test.ideal {
+					ideal[j] = parseMust(variables, o.order, s)
+					mirrored[j] = mapMonomials(ideal[j], Deglex, o.f)
+				}
+				basis, complete := Buchberger(ideal, 100)
+				if !complete {
+					t.Fatalf("incomplete")
+				}
+				want, complete := Buchberger(mirrored, 100)
+				if !complete {
+					t.Fatalf("incomplete")
+				}
+
+				got := make([]*Polynomial[*Rat], len(basis))
+				for j, b := range basis {
+					got[j] = mapMonomials(b, Deglex, o.f)
+				}
+				slices.SortFunc(got, polynomialCmp)
+				if !slices.EqualFunc(got, want, (*Polynomial[*Rat]).Equal) {
+					t.Errorf("got %v want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+// mapMonomials returns the polynomial in order whose monomials are those of p mapped by f.
+func mapMonomials(p *Polynomial[*Rat], order Order, f func(Monomial) Monomial) *Polynomial[*Rat] {
+	q := NewPolynomial(NewRat(0, 1), order)
+	for c, w := range p.Terms() {
+		q.addTerm(1, PolynomialTerm[*Rat]{Coefficient: c, Monomial: f(w)})
+	}
+	return q
+}
+
+// checkAdmissible checks that order is a total order compatible with multiplication on the monomials of symbols up to degree maxDeg.
+func checkAdmissible(order Order, symbols []Symbol, maxDeg int) error {
+	words := []Monomial{{}}
+	for i := 0; i < len(words); i++ {
+		if len(words[i]) == maxDeg {
+			continue
+		}
+		for _, s := range symbols {
+			words = append(words, append(slices.Clone(words[i]), s))
+		}
+	}
+	for _, x := range words {
+		if order(x, x) != 0 {
+			return fmt.Errorf("%v != %v", x, x)
+		}
+		for _, y := range words {
+			c := order(x, y)
+			if c == 0 && !slices.Equal(x, y) {
+				return fmt.Errorf("%v == %v", x, y)
+			}
+			if order(y, x) != -c {
+				return fmt.Errorf("asymmetric %v %v", x, y)
+			}
+			if c >= 0 || len(x) == maxDeg || len(y) == maxDeg {
+				continue
+			}
+			// x < y implies sxt < syt for all symbols s, t.
+			for _, s := range symbols {
+				if order(append(Monomial{s}, x...), append(Monomial{s}, y...)) >= 0 {
+					return fmt.Errorf("%v < %v but not after left multiplication by %v", x, y, s)
+				}
+				if order(append(slices.Clone(x), s), append(slices.Clone(y), s)) >= 0 {
+					return fmt.Errorf("%v < %v but not after right multiplication by %v", x, y, s)
+				}
+			}
+			// The empty monomial is the smallest.
+			if len(x) > 0 && order(Monomial{}, x) >= 0 {
+				return fmt.Errorf("%v <= 1", x)
+			}
+		}
+	}
+	return nil
+}
@@ -84,6 +84,16 @@ func TestParse(t *testing.T) {
 				PolynomialTerm[*Rat]{Coefficient: NewRat(9, 1), Monomial: Monomial{1}},
 			),
 		},
+		{
+			variables: map[string]Symbol{"{a_{1}}": 1, "{a_{257}}": 257, "{a_{1000}}": 1000},
+			order:     Deglex,
+			input:     "{a_{257}}{a_{1}}^2-{a_{1000}}",
+			p: NewPolynomial(
+				NewRat(0, 1), Deglex,
+				PolynomialTerm[*Rat]{Coefficient: NewRat(1, 1), Monomial: Monomial{257, 1, 1}},
+				PolynomialTerm[*Rat]{Coefficient: NewRat(-1, 1), Monomial: Monomial{1000}},
+			),
+		},
 	}
 
 	for i, test := range tests {
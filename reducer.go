@@ -0,0 +1,468 @@
+package nag
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A Reducer divides polynomials by a fixed basis.
+// The leading monomials of the basis are indexed by an [Aho–Corasick automaton], so that a divisor of a monomial is found in time linear in the degree of the monomial, regardless of the size of the basis.
+// A Reducer is safe for concurrent use, provided that the basis is not modified.
+//
+// Aho, Alfred V., and Margaret J. Corasick. "Efficient string matching: an aid to bibliographic search." Communications of the ACM 18.6 (1975): 333-340.
+//
+// [Aho–Corasick automaton]: https://en.wikipedia.org/wiki/Aho%E2%80%93Corasick_algorithm
+type Reducer[K Field[K]] struct {
+	g     []*Polynomial[K]
+	nodes []reducerNode
+	// tails are the sorted term vectors of g without their leading terms, or nil unless r divides with [GeobucketBackend].
+	tails [][]term[K]
+
+	// normalForms caches the normal forms of monomials computed by [Reducer.NormalForm], keyed by [Monomial.key].
+	normalFormsMu sync.Mutex
+	normalForms   map[string]*Polynomial[K]
+}
+
+// A reducerNode is a state in the Aho–Corasick automaton, which corresponds to a prefix of the leading monomials.
+type reducerNode struct {
+	// children are the transitions of the trie.
+	// They are stored in a slice instead of a map, since the number of symbols is usually small.
+	children []reducerEdge
+	// fail is the state of the longest proper suffix of this state that is also a state.
+	fail int
+	// best are the two smallest indices of the leading monomials that are suffixes of this state, or -1 if there are none.
+	// The second smallest index allows skipping one polynomial in the basis, see [Reducer.find].
+	best [2]int
+	// bestLen are the degrees of the leading monomials at best.
+	bestLen [2]int
+}
+
+type reducerEdge struct {
+	symbol Symbol
+	node   int
+}
+
+// child returns the state after reading x from n in the trie, or -1 if there is none.
+func (n *reducerNode) child(x Symbol) int {
+	for _, e := range n.children {
+		if e.symbol == x {
+			return e.node
+		}
+	}
+	return -1
+}
+
+// push updates the best matches of n with the i'th leading monomial, which has degree l.
+func (n *reducerNode) push(i, l int) {
+	switch {
+	case i == n.best[0] || i == n.best[1]:
+	case n.best[0] == -1 || i < n.best[0]:
+		n.best[1], n.bestLen[1] = n.best[0], n.bestLen[0]
+		n.best[0], n.bestLen[0] = i, l
+	case n.best[1] == -1 || i < n.best[1]:
+		n.best[1], n.bestLen[1] = i, l
+	}
+}
+
+// NewReducer returns a reducer for the basis g, which divides with [TreeBackend].
+// Nil polynomials in g are skipped, but still occupy their indices in the quotients returned by the reducer.
+func NewReducer[K Field[K]](g []*Polynomial[K]) *Reducer[K] {
+	return NewReducerBackend(g, TreeBackend)
+}
+
+// NewReducerBackend is like [NewReducer], but keeps the intermediate polynomials of divisions in backend.
+// With [GeobucketBackend], the polynomial divided by the reducer is not modified.
+func NewReducerBackend[K Field[K]](g []*Polynomial[K], backend Backend) *Reducer[K] {
+	r := &Reducer[K]{g: g, nodes: []reducerNode{newReducerNode()}}
+	if backend == GeobucketBackend {
+		r.tails = make([][]term[K], len(g))
+		for i, gi := range g {
+			if gi != nil {
+				r.tails[i] = termVector(gi, true)
+			}
+		}
+	}
+
+	// Build the trie of leading monomials.
+	for i, gi := range g {
+		if gi == nil {
+			continue
+		}
+		w := gi.LeadingTerm().Monomial
+		s := 0
+		for _, x := range w {
+			next := r.nodes[s].child(x)
+			if next == -1 {
+				next = len(r.nodes)
+				r.nodes = append(r.nodes, newReducerNode())
+				r.nodes[s].children = append(r.nodes[s].children, reducerEdge{symbol: x, node: next})
+			}
+			s = next
+		}
+		r.nodes[s].push(i, len(w))
+	}
+
+	// Compute failure links in breadth first order, and propagate the best matches along them.
+	queue := []int{0}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, e := range r.nodes[s].children {
+			queue = append(queue, e.node)
+			if s == 0 {
+				continue
+			}
+			r.nodes[e.node].fail = r.step(r.nodes[s].fail, e.symbol)
+		}
+		if s != 0 {
+			f := r.nodes[r.nodes[s].fail]
+			for k := range f.best {
+				if f.best[k] != -1 {
+					r.nodes[s].push(f.best[k], f.bestLen[k])
+				}
+			}
+		}
+	}
+	return r
+}
+
+func newReducerNode() reducerNode {
+	return reducerNode{best: [2]int{-1, -1}}
+}
+
+// step returns the state after reading x from state s.
+func (r *Reducer[K]) step(s int, x Symbol) int {
+	for {
+		if next := r.nodes[s].child(x); next != -1 {
+			return next
+		}
+		if s == 0 {
+			return 0
+		}
+		s = r.nodes[s].fail
+	}
+}
+
+// find returns the smallest index i other than skip, such that the leading monomial of g[i] divides w, as well as the position of its leftmost occurrence in w.
+// It returns -1 if no leading monomial divides w.
+func (r *Reducer[K]) find(w Monomial, skip int) (i, leftEnd int) {
+	i, leftEnd = -1, -1
+	s := 0
+	// Check the empty prefix before reading w, since a constant polynomial divides every monomial.
+	for end := 0; end <= len(w); end++ {
+		if end > 0 {
+			s = r.step(s, w[end-1])
+		}
+		n := &r.nodes[s]
+		k := 0
+		if n.best[0] == skip {
+			k = 1
+		}
+		// Only a smaller index replaces the current one, so that the leftmost occurrence is kept.
+		if best := n.best[k]; best != -1 && (i == -1 || best < i) {
+			i, leftEnd = best, end-n.bestLen[k]
+			if i == 0 {
+				break
+			}
+		}
+	}
+	return i, leftEnd
+}
+
+// Divide is like [Divide] with the basis of r.
+func (r *Reducer[K]) Divide(quotient [][]Quotient[K], f *Polynomial[K]) (outQuotient [][]Quotient[K], remainder *Polynomial[K]) {
+	outQuotient, remainder, _ = r.divide(nil, quotient, f, -1)
+	return outQuotient, remainder
+}
+
+// DivideContext is like [DivideContext] with the basis of r.
+func (r *Reducer[K]) DivideContext(ctx context.Context, quotient [][]Quotient[K], f *Polynomial[K]) (outQuotient [][]Quotient[K], remainder *Polynomial[K], err error) {
+	outQuotient, remainder, err = r.divide(ctx.Done(), quotient, f, -1)
+	if err != nil {
+		return outQuotient, remainder, errors.Wrap(ctx.Err(), "")
+	}
+	return outQuotient, remainder, nil
+}
+
+// divide implements [Reducer.Divide], and checks before each reduction step whether done is closed.
+// A nil done never closes.
+// The polynomial at index skip of the basis is not used for division, which allows interreduction without rebuilding the reducer.
+func (r *Reducer[K]) divide(done <-chan struct{}, quotient [][]Quotient[K], f *Polynomial[K], skip int) ([][]Quotient[K], *Polynomial[K], error) {
+	g := r.g
+	if quotient != nil {
+		short := len(g) - len(quotient)
+		if short > 0 {
+			quotient = append(quotient, make([][]Quotient[K], short)...)
+		}
+		quotient = quotient[:len(g)]
+		for i := range quotient {
+			quotient[i] = quotient[i][:0]
+		}
+	}
+	p := NewPolynomial[K](f.field, f.order)
+	p.SymbolStringer = f.SymbolStringer
+	v := r.dividend(f)
+
+	for {
+		select {
+		case <-done:
+			return quotient, p, errCanceled
+		default:
+		}
+
+		lmv, ok := v.leadingTerm()
+		if !ok {
+			break
+		}
+		ltv := lmv.Monomial
+
+		// Find basis where ltv = left * ltg * right.
+		basis, leftEnd := r.find(ltv, skip)
+		if basis == -1 {
+			v.moveLeadingTerm(p)
+		} else {
+			lmg := g[basis].LeadingTerm()
+			q := Quotient[K]{
+				Coefficient: f.field.NewZero().Div(lmv.Coefficient, lmg.Coefficient),
+				Left:        ltv[:leftEnd:leftEnd],
+				Right:       ltv[leftEnd+len(lmg.Monomial):],
+			}
+			if quotient != nil {
+				quotient[basis] = append(quotient[basis], q)
+			}
+			v.sub(q.Coefficient, q.Left, basis, q.Right)
+		}
+	}
+
+	return quotient, p, nil
+}
+
+// NormalForm returns the remainder of f divided by the basis of r, without computing the quotient.
+// Unlike [Reducer.Divide], f is not modified.
+// The normal form of each monomial is cached in r, so that monomials shared by many polynomials, or by the reduction steps of a polynomial, are reduced only once.
+// This makes NormalForm suitable for reducing many polynomials by a fixed basis, such as the arithmetic of a quotient ring, at the cost of memory that grows with the number of distinct monomials reduced.
+// If the basis is a Gröbner basis, the remainder is unique and is the same as that of [Reducer.Divide].
+// Otherwise, the remainder may differ from that of [Reducer.Divide], but f minus the remainder still belongs to the ideal of the basis.
+func (r *Reducer[K]) NormalForm(f *Polynomial[K]) *Polynomial[K] {
+	// Use a new field element, since p.field is modified in arithmetic, and f may be shared among goroutines.
+	p := NewPolynomial(f.field.NewZero(), f.order)
+	p.SymbolStringer = f.SymbolStringer
+	for c, w := range f.Terms() {
+		p.add(1, c, nil, r.normalForm(w, f.field, f.order), nil)
+	}
+	return p
+}
+
+// normalForm returns the cached normal form of w, which must not be modified.
+// The normal form of a reducible w = left*lt(g_i)*right is that of the rest of left*g_i*right, which is computed recursively from the normal forms of its monomials.
+func (r *Reducer[K]) normalForm(w Monomial, field K, order Order) *Polynomial[K] {
+	key := w.key()
+	r.normalFormsMu.Lock()
+	nf, ok := r.normalForms[key]
+	r.normalFormsMu.Unlock()
+	if ok {
+		return nf
+	}
+
+	nf = NewPolynomial(field.NewZero(), order)
+	i, leftEnd := r.find(w, -1)
+	if i == -1 {
+		nf.addMonomial(1, field.NewOne(), w)
+	} else {
+		lt := r.g[i].LeadingTerm()
+		left, right := w[:leftEnd:leftEnd], w[leftEnd+len(lt.Monomial):]
+		inv := field.NewZero().Inv(lt.Coefficient)
+		var buf Monomial
+		for gc, gw := range r.g[i].Terms() {
+			if monomialEq(gw, lt.Monomial) {
+				continue
+			}
+			buf = append(append(append(buf[:0], left...), gw...), right...)
+			nf.add(-1, field.NewZero().Mul(inv, gc), nil, r.normalForm(buf, field, order), nil)
+		}
+	}
+
+	r.normalFormsMu.Lock()
+	defer r.normalFormsMu.Unlock()
+	if r.normalForms == nil {
+		r.normalForms = make(map[string]*Polynomial[K])
+	}
+	r.normalForms[key] = nf
+	return nf
+}
+
+// A dividend is the intermediate polynomial of a division, which is reduced by the basis until it vanishes.
+type dividend[K Field[K]] interface {
+	// leadingTerm returns the leading term of the dividend, or false if it is zero.
+	leadingTerm() (PolynomialTerm[K], bool)
+	// moveLeadingTerm moves the leading term of the dividend to the remainder p.
+	moveLeadingTerm(p *Polynomial[K])
+	// sub subtracts c*left*g[i]*right from the dividend, where g is the basis, and the leading terms cancel.
+	sub(c K, left Monomial, i int, right Monomial)
+	// mulScalar multiplies the dividend by scalar.
+	mulScalar(scalar K)
+}
+
+// dividend returns f as the intermediate polynomial of a division, in the backend of r.
+func (r *Reducer[K]) dividend(f *Polynomial[K]) dividend[K] {
+	if r.tails != nil {
+		return &geobucketDividend[K]{geobucket: newGeobucket(f), tails: r.tails}
+	}
+	return &treeDividend[K]{v: f, g: r.g}
+}
+
+// A treeDividend is a dividend in [TreeBackend], which modifies the polynomial v in place.
+type treeDividend[K Field[K]] struct {
+	v *Polynomial[K]
+	g []*Polynomial[K]
+}
+
+func (d *treeDividend[K]) leadingTerm() (PolynomialTerm[K], bool) {
+	if d.v.m.Len() == 0 {
+		return PolynomialTerm[K]{}, false
+	}
+	return d.v.LeadingTerm(), true
+}
+
+func (d *treeDividend[K]) moveLeadingTerm(p *Polynomial[K]) {
+	lt := d.v.LeadingTerm()
+	p.addTerm(1, lt)
+	d.v.addTerm(-1, lt)
+}
+
+func (d *treeDividend[K]) sub(c K, left Monomial, i int, right Monomial) {
+	d.v.add(-1, c, left, d.g[i], right)
+}
+
+func (d *treeDividend[K]) mulScalar(scalar K) { d.v.mulScalar(scalar, d.v) }
+
+// A geobucketDividend is a dividend in [GeobucketBackend].
+// Instead of canceling the leading terms in sub, it removes the leading term of the dividend, and subtracts only the tails of the basis.
+type geobucketDividend[K Field[K]] struct {
+	*geobucket[K]
+	tails [][]term[K]
+}
+
+func (d *geobucketDividend[K]) leadingTerm() (PolynomialTerm[K], bool) {
+	t, ok := d.geobucket.leadingTerm()
+	var w Monomial
+	if ok {
+		w = t.w.monomial()
+	}
+	return PolynomialTerm[K]{Coefficient: t.c, Monomial: w}, ok
+}
+
+func (d *geobucketDividend[K]) moveLeadingTerm(p *Polynomial[K]) {
+	t, _ := d.geobucket.leadingTerm()
+	p.addInterned(1, t.c, t.w)
+	d.pop()
+}
+
+func (d *geobucketDividend[K]) sub(c K, left Monomial, i int, right Monomial) {
+	d.pop()
+	d.geobucket.sub(c, left, d.tails[i], right)
+}
+
+// divideFractionFree is like divide, but avoids divisions of coefficients, in the style of pseudo-division.
+// Instead of dividing by the leading coefficient of a basis polynomial, each reduction step multiplies the intermediate polynomial by the leading coefficient, after cancelling their greatest common divisor using gcd.
+// The returned scale and quotient satisfy:
+//
+//	scale*f = g*quotient + remainder
+//
+// As with divide, f is modified upon return, unless r divides with [GeobucketBackend].
+func (r *Reducer[K]) divideFractionFree(done <-chan struct{}, quotient [][]Quotient[K], f *Polynomial[K], skip int, gcd func(x, y K) K) ([][]Quotient[K], *Polynomial[K], K, error) {
+	g := r.g
+	if quotient != nil {
+		short := len(g) - len(quotient)
+		if short > 0 {
+			quotient = append(quotient, make([][]Quotient[K], short)...)
+		}
+		quotient = quotient[:len(g)]
+		for i := range quotient {
+			quotient[i] = quotient[i][:0]
+		}
+	}
+	p := NewPolynomial[K](f.field, f.order)
+	p.SymbolStringer = f.SymbolStringer
+	v := r.dividend(f)
+	one := f.field.NewOne()
+
+	// steps record the quotients and the scales multiplied at each reduction step.
+	// The coefficient of a quotient is multiplied by the scales of all later steps.
+	type step struct {
+		basis int
+		q     Quotient[K]
+		scale K
+	}
+	var steps []step
+	for {
+		select {
+		case <-done:
+			return quotient, p, one, errCanceled
+		default:
+		}
+
+		lmv, ok := v.leadingTerm()
+		if !ok {
+			break
+		}
+		ltv := lmv.Monomial
+
+		// Find basis where ltv = left * ltg * right.
+		basis, leftEnd := r.find(ltv, skip)
+		if basis == -1 {
+			v.moveLeadingTerm(p)
+			continue
+		}
+
+		lmg := g[basis].LeadingTerm()
+		d := gcd(lmv.Coefficient, lmg.Coefficient)
+		scale := f.field.NewZero().Div(lmg.Coefficient, d)
+		q := Quotient[K]{
+			Coefficient: f.field.NewZero().Div(lmv.Coefficient, d),
+			Left:        ltv[:leftEnd:leftEnd],
+			Right:       ltv[leftEnd+len(lmg.Monomial):],
+		}
+		if !scale.Equal(one) {
+			v.mulScalar(scale)
+			p.mulScalar(scale, p)
+		}
+		v.sub(q.Coefficient, q.Left, basis, q.Right)
+		steps = append(steps, step{basis: basis, q: q, scale: scale})
+	}
+
+	total := f.field.NewOne()
+	for k := len(steps) - 1; k >= 0; k-- {
+		if quotient != nil {
+			q := steps[k].q
+			q.Coefficient = q.Coefficient.Mul(q.Coefficient, total)
+			quotient[steps[k].basis] = append(quotient[steps[k].basis], q)
+		}
+		total = total.Mul(total, steps[k].scale)
+	}
+	if quotient != nil {
+		for i := range quotient {
+			slices.Reverse(quotient[i])
+		}
+	}
+	return quotient, p, total, nil
+}
+
+// primitive divides f by its content, which is the greatest common divisor of its coefficients, and returns the content.
+// The content of the zero polynomial is one.
+func primitive[K Field[K]](f *Polynomial[K], gcd func(x, y K) K) K {
+	if f.m.Len() == 0 {
+		return f.field.NewOne()
+	}
+	content := f.field.NewZero()
+	for c := range f.Terms() {
+		content = gcd(content, c)
+	}
+	if !content.Equal(f.field.NewOne()) {
+		f.mulScalar(f.field.NewZero().Inv(content), f)
+	}
+	return content
+}
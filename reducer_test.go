@@ -0,0 +1,179 @@
+package nag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReducerFind(t *testing.T) {
+	variables := map[string]Symbol{"a": 1, "b": 2, "c": 3}
+	tests := []struct {
+		g []string
+	}{
+		{g: []string{"a"}},
+		{g: []string{"ab", "b", "ba"}},
+		{g: []string{"aba - b", "bab - b", "ba - ab", "b^2 - ab"}},
+		{g: []string{"abc", "bc", "c", "bca", "aa"}},
+		{g: []string{"ab", "2", "b"}},
+		// Duplicate leading monomials.
+		{g: []string{"ab + a", "ab", "b^2a", "ab + b"}},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			g := make([]*Polynomial[*Rat], len(test.g))
+			for j, s := range test.g {
+				g[j] = parseMust(variables, Deglex, s)
+			}
+			r := NewReducer(g)
+
+			// Compare against a linear scan for all words up to degree 6.
+			words := []Monomial{{}}
+			for range 7 {
+				var next []Monomial
+				for _, w := range words {
+					for skip := -1; skip < len(g); skip++ {
+						wantI, wantLeftEnd := findLinear(w, g, skip)
+						if i, leftEnd := r.find(w, skip); i != wantI || leftEnd != wantLeftEnd {
+							t.Fatalf("%v %d: got %d %d want %d %d", w, skip, i, leftEnd, wantI, wantLeftEnd)
+						}
+					}
+					for _, x := range []Symbol{1, 2, 3} {
+						next = append(next, append(append(Monomial{}, w...), x))
+					}
+				}
+				words = next
+			}
+		})
+	}
+}
+
+// findLinear is the reference implementation of Reducer.find, which scans the basis linearly.
+func findLinear[K Field[K]](w Monomial, g []*Polynomial[K], skip int) (int, int) {
+	for i, gi := range g {
+		if gi == nil || i == skip {
+			continue
+		}
+		if leftEnd := monomialIndex(w, gi.LeadingTerm().Monomial); leftEnd != -1 {
+			return i, leftEnd
+		}
+	}
+	return -1, -1
+}
+
+func TestReducerDivide(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "z": 1}
+	g := []*Polynomial[*Rat]{parseMust(variables, Deglex, "xy + x"), nil, parseMust(variables, Deglex, "x^2 + xz")}
+	tests := []struct {
+		f         string
+		remainder string
+	}{
+		{f: "zx^2yx", remainder: "zxzx"},
+		{f: "x^3 + y", remainder: "-xzx + y"},
+		{f: "yz", remainder: "yz"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			for _, backend := range []Backend{TreeBackend, GeobucketBackend} {
+				r := NewReducerBackend(g, backend)
+				f := parseMust(variables, Deglex, test.f)
+				quotient, remainder := r.Divide([][]Quotient[*Rat]{}, f)
+				if want := parseMust(variables, Deglex, test.remainder); !remainder.Equal(want) {
+					t.Errorf("%v: got %v want %v", backend, remainder, want)
+				}
+				if len(quotient) != len(g) || len(quotient[1]) != 0 {
+					t.Fatalf("%v", quotient)
+				}
+
+				// Check f = g*quotient + remainder.
+				sum := NewPolynomial(remainder.field.NewZero(), remainder.order).Set(remainder)
+				for j := range quotient {
+					for _, q := range quotient[j] {
+						sum.add(1, q.Coefficient, q.Left, g[j], q.Right)
+					}
+				}
+				if want := parseMust(variables, Deglex, test.f); !sum.Equal(want) {
+					t.Errorf("%v: got %v want %v", backend, sum, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReducerDivideFractionFree(t *testing.T) {
+	variables := map[string]Symbol{"x": 3, "y": 2, "z": 1}
+	g := []*Polynomial[*Rat]{parseMust(variables, Deglex, "2xy + 3x"), parseMust(variables, Deglex, "4x^2 + 6xz")}
+	gcd := gcdFunc(NewRat(0, 1))
+	tests := []struct {
+		f         string
+		remainder string
+	}{
+		{f: "zx^2yx", remainder: "18zxzx"},
+		{f: "x^3 + y", remainder: "-6xzx + 4y"},
+		{f: "1/2xy", remainder: "-3x"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			for _, backend := range []Backend{TreeBackend, GeobucketBackend} {
+				r := NewReducerBackend(g, backend)
+				f := parseMust(variables, Deglex, test.f)
+				quotient, remainder, scale, err := r.divideFractionFree(nil, [][]Quotient[*Rat]{}, f, -1, gcd)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				if want := parseMust(variables, Deglex, test.remainder); !remainder.Equal(want) {
+					t.Errorf("%v: got %v want %v", backend, remainder, want)
+				}
+
+				// Check scale*f = g*quotient + remainder.
+				sum := NewPolynomial(remainder.field.NewZero(), remainder.order).Set(remainder)
+				for j := range quotient {
+					for _, q := range quotient[j] {
+						sum.add(1, q.Coefficient, q.Left, g[j], q.Right)
+					}
+				}
+				want := parseMust(variables, Deglex, test.f)
+				want.mulScalar(scale, want)
+				if !sum.Equal(want) {
+					t.Errorf("%v: got %v want %v", backend, sum, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReducerNormalForm(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	// Example 5.12, Mora.
+	basis, complete := Buchberger([]*Polynomial[*Rat]{parseMust(variables, Deglex, "aba - b"), parseMust(variables, Deglex, "bab - b")}, 50)
+	if !complete {
+		t.Fatalf("not complete")
+	}
+	r := NewReducer(basis)
+	tests := []struct {
+		f string
+	}{
+		{f: "b^2a^2 - a^2b^2 + aba"},
+		{f: "a^3b^2 - 2b"},
+		{f: "ba^2b + 3b^2a + a"},
+		{f: "a^5b^5a^5"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			f := parseMust(variables, Deglex, test.f)
+			nf := r.NormalForm(f)
+			if want := parseMust(variables, Deglex, test.f); !f.Equal(want) {
+				t.Errorf("modified f %v want %v", f, want)
+			}
+
+			// The normal form by a Gröbner basis is the same as the remainder.
+			_, remainder := r.Divide(nil, f)
+			if !nf.Equal(remainder) {
+				t.Errorf("got %v want %v", nf, remainder)
+			}
+		})
+	}
+}
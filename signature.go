@@ -0,0 +1,383 @@
+package nag
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// SignatureGB returns the Gröbner basis of the ideal g, using a signature-based algorithm in the style of F5.
+// Each polynomial in the computation is labeled with a signature, which records how the polynomial is obtained from g.
+// Signatures allow S-polynomials that would reduce to zero to be detected and discarded before any reduction takes place.
+// The returned basis is monic and sorted in the same way as [Buchberger].
+// Since a noncommutative Gröbner basis may not be finite, at most maxIter polynomials are reduced.
+// Upon reaching maxIter without a complete basis, SignatureGB sets complete to false.
+// For more details, please see Algorithm 1, Hofstadler and Verron.
+//
+// Hofstadler, Clemens, and Thibaut Verron. "Signature Gröbner bases, bases of syzygies and cofactor reconstruction in the free algebra." Journal of Symbolic Computation 113 (2022): 211-241.
+func SignatureGB[K Field[K]](g []*Polynomial[K], maxIter int) (basis []*Polynomial[K], complete bool) {
+	s := newSignatureState(g)
+	complete = s.run(maxIter)
+
+	basis = make([]*Polynomial[K], 0, len(s.basis))
+	for _, f := range s.basis {
+		basis = append(basis, NewPolynomial(f.field, f.order).Set(f))
+	}
+	basis = interreduce(basis)
+	return monicSorted(basis), complete
+}
+
+// newSignatureState returns the state of a signature Gröbner basis computation of the ideal g, whose pairs are the nonzero polynomials in g.
+func newSignatureState[K Field[K]](g []*Polynomial[K]) *signatureState[K] {
+	s := &signatureState[K]{order: g[0].order, reduced: make(map[string]bool)}
+	s.pairs.cmp = s.sigCmp
+	for _, f := range g {
+		if f.Len() == 0 {
+			continue
+		}
+		lm := f.LeadingTerm().Monomial
+		heap.Push(&s.pairs, sigPair{sig: signature{i: len(s.input)}, word: lm, k: -1, l: -1})
+		s.input = append(s.input, f)
+	}
+	return s
+}
+
+// run reduces at most maxIter pairs, and reports whether the basis is complete.
+func (s *signatureState[K]) run(maxIter int) (complete bool) {
+	// Signature Gröbner bases may be infinite even if the Gröbner basis is finite.
+	// Therefore, check whether the basis is already a Gröbner basis each time the pairs reach a larger degree.
+	// At that point the basis is a signature Gröbner basis up to the signature of the next pair, which limits the check to S-polynomials of larger signatures, see [signatureState.groebner].
+	var degree, iter int
+	for iter < maxIter {
+		if s.pairs.Len() == 0 {
+			return true
+		}
+		if next := s.pairs.s[0]; len(next.word) > degree {
+			degree = len(next.word)
+			if len(s.basis) > 0 && s.groebner(next) {
+				return true
+			}
+		}
+		p, ok := s.nextPair()
+		if !ok {
+			continue
+		}
+		iter++
+
+		f, ok := s.reduce(p)
+		if !ok {
+			continue
+		}
+		if f.Len() == 0 {
+			s.syzygies = append(s.syzygies, p.sig)
+			continue
+		}
+		s.add(p.sig, p.word, f)
+	}
+	return false
+}
+
+// A signature represents the module element left * e_i * right, where e_i is the i'th unit vector.
+type signature struct {
+	left  Monomial
+	i     int
+	right Monomial
+}
+
+// divides reports whether x divides y, that is whether y = u * x * v for some monomials u and v.
+func (x signature) divides(y signature) bool {
+	if x.i != y.i {
+		return false
+	}
+	if _, ok := cutSuffix(y.left, x.left); !ok {
+		return false
+	}
+	if _, ok := cutPrefix(y.right, x.right); !ok {
+		return false
+	}
+	return true
+}
+
+// A sigPair is a candidate polynomial waiting to be reduced.
+// It is either the S-polynomial of an obstruction between basis polynomials k and l, or the input polynomial sig.i if k == -1.
+// The signature of an S-polynomial is that of kLeft * basis[k] * kRight, which is larger than that of lLeft * basis[l] * lRight.
+type sigPair struct {
+	sig signature
+	// word is sig.left * lm(input[sig.i]) * sig.right, which is cached for comparing signatures.
+	word Monomial
+
+	k, l   int
+	kLeft  Monomial
+	kRight Monomial
+	lLeft  Monomial
+	lRight Monomial
+}
+
+type signatureState[K Field[K]] struct {
+	order Order
+	input []*Polynomial[K]
+
+	// basis are polynomials labeled by sigs and words.
+	basis []*Polynomial[K]
+	lms   []Monomial
+	sigs  []signature
+	words []Monomial
+	// syzygies are signatures of polynomials that reduced to zero.
+	syzygies []signature
+	pairs    sigPairHeap
+	// reduced are the obstructions of the basis whose S-polynomials are known to reduce to zero.
+	reduced map[string]bool
+}
+
+// sigCmp compares signatures by first comparing their words, then their positions, and finally their left monomials.
+// This order is compatible with multiplication, in the sense that sigCmp(x, y) < 0 implies sigCmp(u*x*v, u*y*v) < 0.
+func (s *signatureState[K]) sigCmp(x, y sigPair) int {
+	if c := s.order(x.word, y.word); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(x.sig.i, y.sig.i); c != 0 {
+		return c
+	}
+	return s.order(x.sig.left, y.sig.left)
+}
+
+// nextPair pops the pair with the smallest signature.
+// It reports false if the pair can be discarded, because its signature is divisible by a syzygy or it can be rewritten by a later basis polynomial.
+func (s *signatureState[K]) nextPair() (sigPair, bool) {
+	p := heap.Pop(&s.pairs).(sigPair)
+	// Among pairs with the same signature, keep the first one that is not rewritable.
+	found := !s.discardable(p)
+	for s.pairs.Len() > 0 && s.sigCmp(s.pairs.s[0], p) == 0 {
+		q := heap.Pop(&s.pairs).(sigPair)
+		if !found && !s.discardable(q) {
+			p, found = q, true
+		}
+	}
+	return p, found
+}
+
+func (s *signatureState[K]) discardable(p sigPair) bool {
+	for _, h := range s.syzygies {
+		if h.divides(p.sig) {
+			return true
+		}
+	}
+	if s.koszul(p) {
+		return true
+	}
+	if p.k == -1 {
+		return false
+	}
+	return s.rewritable(p.k, p.sig)
+}
+
+// koszul reports whether the signature of p is a multiple of the signature of a trivial syzygy basis[k]*m*basis[l] - basis[k]*m*basis[l].
+// The signature of such a syzygy is the larger of lm(basis[k])*m*sigs[l] and sigs[k]*m*lm(basis[l]).
+func (s *signatureState[K]) koszul(p sigPair) bool {
+	for l, sl := range s.sigs {
+		if !sl.divides(p.sig) {
+			continue
+		}
+		lml := s.lms[l]
+		a, _ := cutSuffix(p.sig.left, sl.left)
+		b, _ := cutPrefix(p.sig.right, sl.right)
+		for k, lmk := range s.lms {
+			// Check if p is a multiple of lm(basis[k])*m*sigs[l].
+			for i := range factorIndices(a, lmk) {
+				m := a[i+len(lmk):]
+				x := s.mulSig(l, concat(lmk, m), nil)
+				y := s.mulSig(k, nil, concat(m, lml))
+				if s.sigCmp(x, y) > 0 {
+					return true
+				}
+			}
+			// Check if p is a multiple of sigs[l]*m*lm(basis[k]).
+			for i := range factorIndices(b, lmk) {
+				m := b[:i]
+				x := s.mulSig(l, nil, concat(m, lmk))
+				y := s.mulSig(k, concat(lml, m), nil)
+				if s.sigCmp(x, y) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// rewritable reports whether there is a basis polynomial added later than k whose signature divides sig.
+func (s *signatureState[K]) rewritable(k int, sig signature) bool {
+	for m := k + 1; m < len(s.sigs); m++ {
+		if s.sigs[m].divides(sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// reduce computes the polynomial of p and reduces it, using only reductions by multiples of basis polynomials with smaller signatures.
+// It reports false if the reduced polynomial is redundant, because its leading monomial is reducible by a multiple with the same signature.
+func (s *signatureState[K]) reduce(p sigPair) (*Polynomial[K], bool) {
+	var f *Polynomial[K]
+	if p.k == -1 {
+		in := s.input[p.sig.i]
+		f = NewPolynomial(in.field.NewZero(), in.order).Set(in)
+	} else {
+		gk, gl := s.basis[p.k], s.basis[p.l]
+		r0 := gk.field.NewZero()
+		f = NewPolynomial(gk.field.NewZero(), gk.order)
+		f.SymbolStringer = gk.SymbolStringer
+		f.add(1, r0.Inv(gk.LeadingTerm().Coefficient), p.kLeft, gk, p.kRight)
+		f.add(-1, r0.Inv(gl.LeadingTerm().Coefficient), p.lLeft, gl, p.lRight)
+	}
+
+	for f.Len() != 0 {
+		lt := f.LeadingTerm()
+		m, left, right, singular := s.reducer(lt.Monomial, p)
+		if m == -1 {
+			if singular {
+				return nil, false
+			}
+			break
+		}
+		gm := s.basis[m]
+		c := f.field.NewZero().Div(lt.Coefficient, gm.LeadingTerm().Coefficient)
+		f.add(-1, c, left, gm, right)
+	}
+	return f, true
+}
+
+// reducer finds a basis polynomial m such that w = left * lm(basis[m]) * right, and left * sigs[m] * right is smaller than the signature of p.
+// If no such polynomial exists, reducer returns m = -1, and reports whether w is divisible by a multiple with the same signature as p.
+func (s *signatureState[K]) reducer(w Monomial, p sigPair) (m int, left, right Monomial, singular bool) {
+	for m, lm := range s.lms {
+		for i := range factorIndices(w, lm) {
+			left, right = w[:i:i], w[i+len(lm):]
+
+			switch c := s.sigCmp(s.mulSig(m, left, right), p); {
+			case c < 0:
+				return m, left, right, false
+			case c == 0:
+				singular = true
+			}
+		}
+	}
+	return -1, nil, nil, singular
+}
+
+// groebner reports whether the basis is a Gröbner basis, given that it is a signature Gröbner basis up to the signature of next.
+// S-polynomials with signatures smaller than that of next are sig-reducible to zero, and therefore have standard representations.
+// Hence only the S-polynomials with signatures at least that of next are reduced, among the obstructions that remain after the criteria of Theorem 4.2.22, Xiu Xingqiang.
+// The input polynomials are also checked, since those that have not been processed yet may not belong to the ideal of the basis.
+func (s *signatureState[K]) groebner(next sigPair) bool {
+	reducer := NewReducer(s.basis)
+	for _, f := range s.input {
+		f = NewPolynomial(f.field.NewZero(), f.order).Set(f)
+		if _, r := reducer.Divide(nil, f); r.Len() != 0 {
+			return false
+		}
+	}
+
+	var obs []obstruction[K]
+	buf := &Monomial{}
+	for l := 1; l <= len(s.basis); l++ {
+		obs = addObstructions(obs, s.basis[:l], buf, nil)
+	}
+	r0 := s.basis[0].field.NewZero()
+	for _, o := range obs {
+		sig := s.mulSig(o.i, o.iLeft, o.iRight)
+		if x := s.mulSig(o.j, o.jLeft, o.jRight); s.sigCmp(x, sig) > 0 {
+			sig = x
+		}
+		if s.sigCmp(sig, next) < 0 {
+			continue
+		}
+
+		// S-polynomials that reduce to zero keep doing so as the basis grows, and need not be reduced again.
+		key := fmt.Sprintf("%d %d %q %q %q %q", o.i, o.j, o.iLeft.key(), o.iRight.key(), o.jLeft.key(), o.jRight.key())
+		if s.reduced[key] {
+			continue
+		}
+		if _, r := reducer.Divide(nil, sPolynomial(o, s.basis, r0, nil)); r.Len() != 0 {
+			return false
+		}
+		s.reduced[key] = true
+	}
+	return true
+}
+
+// mulSig returns a pair with the signature and word of left * basis[m] * right.
+func (s *signatureState[K]) mulSig(m int, left, right Monomial) sigPair {
+	sig := signature{left: concat(left, s.sigs[m].left), i: s.sigs[m].i, right: concat(s.sigs[m].right, right)}
+	return sigPair{sig: sig, word: concat(left, s.words[m], right)}
+}
+
+// add adds f with signature sig to the basis, and adds the obstructions between f and the basis to the set of pairs.
+func (s *signatureState[K]) add(sig signature, word Monomial, f *Polynomial[K]) {
+	s.basis = append(s.basis, f)
+	s.lms = append(s.lms, f.LeadingTerm().Monomial)
+	s.sigs = append(s.sigs, sig)
+	s.words = append(s.words, word)
+
+	for _, o := range overlapObstruction(nil, s.basis) {
+		k := s.mulSig(o.i, o.iLeft, o.iRight)
+		l := s.mulSig(o.j, o.jLeft, o.jRight)
+
+		// Pairs whose multiples have the same signature are not regular, and are not needed for a signature Gröbner basis.
+		c := s.sigCmp(k, l)
+		if c == 0 {
+			continue
+		}
+		p := sigPair{sig: k.sig, word: k.word, k: o.i, kLeft: o.iLeft, kRight: o.iRight, l: o.j, lLeft: o.jLeft, lRight: o.jRight}
+		if c < 0 {
+			p = sigPair{sig: l.sig, word: l.word, k: o.j, kLeft: o.jLeft, kRight: o.jRight, l: o.i, lLeft: o.iLeft, lRight: o.iRight}
+		}
+		heap.Push(&s.pairs, p)
+	}
+}
+
+// factorIndices returns the positions of y in x.
+func factorIndices(x, y Monomial) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for start := 0; start+len(y) <= len(x); start++ {
+			i := monomialIndex(x[start:], y)
+			if i == -1 {
+				return
+			}
+			start += i
+			if !yield(start) {
+				return
+			}
+		}
+	}
+}
+
+func concat(ws ...Monomial) Monomial {
+	var n int
+	for _, w := range ws {
+		n += len(w)
+	}
+	c := make(Monomial, 0, n)
+	for _, w := range ws {
+		c = append(c, w...)
+	}
+	return c
+}
+
+// A sigPairHeap is a priority queue of pairs ordered by signature.
+type sigPairHeap struct {
+	s   []sigPair
+	cmp func(x, y sigPair) int
+}
+
+func (h *sigPairHeap) Len() int           { return len(h.s) }
+func (h *sigPairHeap) Less(i, j int) bool { return h.cmp(h.s[i], h.s[j]) < 0 }
+func (h *sigPairHeap) Swap(i, j int)      { h.s[i], h.s[j] = h.s[j], h.s[i] }
+func (h *sigPairHeap) Push(x any)         { h.s = append(h.s, x.(sigPair)) }
+func (h *sigPairHeap) Pop() any {
+	x := h.s[len(h.s)-1]
+	h.s = slices.Delete(h.s, len(h.s)-1, len(h.s))
+	return x
+}
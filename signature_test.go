@@ -0,0 +1,91 @@
+package nag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSignatureGB(t *testing.T) {
+	// Only complete bases are comparable, since maxiter counts different things in SignatureGB.
+	// The long cases are skipped, since their signature Gröbner bases are much larger than their Gröbner bases.
+	testBuchbergerCases(t, true, func(t *testing.T, test buchbergerTest) ([]*Polynomial[*Rat], bool) {
+		ideal := make([]*Polynomial[*Rat], len(test.ideal))
+		copy(ideal, test.ideal)
+		return SignatureGB(ideal, 1<<20)
+	})
+}
+
+func TestSignatureGBRedundant(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	// Example 5.12, Mora.
+	base := []string{"aba - b", "bab - b"}
+	tests := []struct {
+		redundant string
+	}{
+		{redundant: "aba - b"},
+		{redundant: "2aba - 2b"},
+		{redundant: "a^2ba - ab"},
+		{redundant: "aba - bab"},
+	}
+	baseIdeal := make([]*Polynomial[*Rat], len(base))
+	for i, s := range base {
+		baseIdeal[i] = parseMust(variables, Deglex, s)
+	}
+	want := newSignatureState(baseIdeal)
+	want.run(1 << 20)
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			ideal := append(append([]*Polynomial[*Rat]{}, baseIdeal...), parseMust(variables, Deglex, test.redundant))
+			s := newSignatureState(ideal)
+			if !s.run(1 << 20) {
+				t.Fatalf("not complete")
+			}
+
+			// The redundant generator reduces to zero with its own signature, and the rest of the computation is the same as without it.
+			if len(s.basis) != len(want.basis) {
+				t.Errorf("got %v want %v", s.basis, want.basis)
+			}
+			if len(s.syzygies) != len(want.syzygies)+1 {
+				t.Fatalf("got %v want %v", s.syzygies, want.syzygies)
+			}
+			if sig := s.syzygies[0]; sig.i != len(base) || len(sig.left) != 0 || len(sig.right) != 0 {
+				t.Errorf("%v", s.syzygies)
+			}
+
+			basis, _ := SignatureGB(ideal, 1<<20)
+			wantBasis, _ := Buchberger(baseIdeal, 1000)
+			if !polynomialsEqual(basis, wantBasis) {
+				t.Errorf("got %v want %v", basis, wantBasis)
+			}
+		})
+	}
+}
+
+func TestSignatureDiscardable(t *testing.T) {
+	variables := map[string]Symbol{"a": 2, "b": 1}
+	ideal := []*Polynomial[*Rat]{parseMust(variables, Deglex, "aba - b"), parseMust(variables, Deglex, "bab - b")}
+	s := newSignatureState(ideal)
+	s.syzygies = []signature{{left: Monomial{1}, i: 1}}
+	tests := []struct {
+		sig         signature
+		discardable bool
+	}{
+		{sig: signature{left: Monomial{1}, i: 1}, discardable: true},
+		{sig: signature{left: Monomial{2, 1}, i: 1, right: Monomial{2}}, discardable: true},
+		// The syzygy is of a different generator.
+		{sig: signature{left: Monomial{1}, i: 0}, discardable: false},
+		// The syzygy does not divide the signature.
+		{sig: signature{left: Monomial{2}, i: 1}, discardable: false},
+		{sig: signature{i: 1, right: Monomial{1}}, discardable: false},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			t.Parallel()
+			p := sigPair{sig: test.sig, k: -1, l: -1}
+			if got := s.discardable(p); got != test.discardable {
+				t.Errorf("got %v want %v", got, test.discardable)
+			}
+		})
+	}
+}